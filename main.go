@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"mermaid2plantuml/parser"
 	"mermaid2plantuml/plantuml"
@@ -22,11 +25,13 @@ func run() error {
 	// コマンドライン引数の解析
 	format := flag.String("format", "png", "出力フォーマット (png|svg|pdf)")
 	output := flag.String("o", "", "出力ファイルパス")
+	direction := flag.String("direction", "", "図の向き (TB|BT|LR|RL)。Mermaidのdirection指定を上書きします")
+	mode := flag.String("mode", "default", "変換モード (default|strict|lenient)。defaultは解釈できない行をエラーに、strictは変換できない指定もエラーに、lenientは解釈できない行も警告にします")
 	flag.Parse()
 
 	// 入力ファイルの確認
 	if flag.NArg() < 1 {
-		return fmt.Errorf("使用方法: mmd2img [-format=<png|svg|pdf>] [-o output_file] input.mmd")
+		return fmt.Errorf("使用方法: mmd2img [-format=<png|svg|pdf>] [-o output_file] [-direction=<TB|BT|LR|RL>] [-mode=<default|strict|lenient>] input.mmd")
 	}
 
 	inputFile := flag.Arg(0)
@@ -42,10 +47,26 @@ func run() error {
 
 	// Mermaid → PlantUML変換
 	p := parser.NewMermaidParser()
-	pumlContent, err := p.ParseToPlantUML(string(input))
+	if *direction != "" {
+		if err := p.SetDirection(*direction); err != nil {
+			return err
+		}
+	}
+	if err := p.SetMode(*mode); err != nil {
+		return err
+	}
+	pumlContent, warnings, err := p.Convert(string(input))
 	if err != nil {
+		var parseErrs parser.ParseErrors
+		if errors.As(err, &parseErrs) {
+			parseErrs.SetFile(inputFile)
+			printParseErrors(os.Stderr, parseErrs, "")
+			return fmt.Errorf("Mermaid形式の解析に失敗: %d件のエラーがあります", len(parseErrs))
+		}
 		return fmt.Errorf("Mermaid形式の解析に失敗: %v", err)
 	}
+	warnings.SetFile(inputFile)
+	printParseErrors(os.Stderr, warnings, "警告: ")
 
 	// 出力ファイル名の決定
 	var outputPuml string
@@ -80,3 +101,29 @@ func run() error {
 
 	return nil
 }
+
+// printParseErrors は解析エラーや警告をコンパイラと同じ形式（file:line:column: message）で出力します
+// label はメッセージの前に付ける種別（"警告: " など）で、エラーの行のソースと位置を示す ^ も続けて出力します
+func printParseErrors(w io.Writer, errs parser.ParseErrors, label string) {
+	for _, e := range errs {
+		fmt.Fprintf(w, "%s:%d:%d: %s%s\n", e.File, e.Line, e.Column, label, e.Message)
+		if e.Snippet == "" {
+			continue
+		}
+		fmt.Fprintf(w, "    %s\n", e.Snippet)
+
+		// タブはそのまま残し、エラー位置までの文字を空白に置き換えて ^ の位置を合わせる
+		var marker strings.Builder
+		for i, r := range []rune(e.Snippet) {
+			if i >= e.Column-1 {
+				break
+			}
+			if r == '\t' {
+				marker.WriteRune('\t')
+			} else {
+				marker.WriteRune(' ')
+			}
+		}
+		fmt.Fprintf(w, "    %s^\n", marker.String())
+	}
+}
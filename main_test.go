@@ -1,10 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"mermaid2plantuml/parser"
 )
 
 func TestMainIntegration(t *testing.T) {
@@ -36,6 +39,11 @@ func TestMainIntegration(t *testing.T) {
 		t.Fatalf("テストファイルの作成に失敗: %v", err)
 	}
 
+	invalidMmd := filepath.Join(tempDir, "invalid.mmd")
+	if err := os.WriteFile(invalidMmd, []byte("classDiagram\nA <-.- B\nC --> D"), 0644); err != nil {
+		t.Fatalf("テストファイルの作成に失敗: %v", err)
+	}
+
 	// 現在のワーキングディレクトリを保存
 	pwd, err := os.Getwd()
 	if err != nil {
@@ -68,6 +76,36 @@ func TestMainIntegration(t *testing.T) {
 			args:    []string{"-o", "output.png", filepath.Base(mmdFile)},
 			wantErr: wantPlantUMLErr, // PlantUMLが利用できない場合はエラー
 		},
+		{
+			name:    "図の向きを指定",
+			args:    []string{"-direction", "LR", filepath.Base(mmdFile)},
+			wantErr: wantPlantUMLErr, // PlantUMLが利用できない場合はエラー
+		},
+		{
+			name:    "不正な図の向き",
+			args:    []string{"-direction", "XY", filepath.Base(mmdFile)},
+			wantErr: true,
+		},
+		{
+			name:    "構文エラー",
+			args:    []string{filepath.Base(invalidMmd)},
+			wantErr: true,
+		},
+		{
+			name:    "lenientモードでは構文エラーを警告にする",
+			args:    []string{"-mode", "lenient", filepath.Base(invalidMmd)},
+			wantErr: wantPlantUMLErr, // PlantUMLが利用できない場合はエラー
+		},
+		{
+			name:    "strictモード",
+			args:    []string{"-mode", "strict", filepath.Base(mmdFile)},
+			wantErr: wantPlantUMLErr, // PlantUMLが利用できない場合はエラー
+		},
+		{
+			name:    "不正な変換モード",
+			args:    []string{"-mode", "loose", filepath.Base(mmdFile)},
+			wantErr: true,
+		},
 		{
 			name:    "存在しないファイル",
 			args:    []string{"nonexistent.mmd"},
@@ -108,3 +146,27 @@ func TestMainIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintParseErrors(t *testing.T) {
+	errs := parser.ParseErrors{
+		{File: "order.mmd", Line: 12, Column: 5, Snippet: "\tA <-.- B", Code: parser.ErrUnknownArrow, Message: `解釈できない関連の矢印です: "<-.-"`},
+		{File: "order.mmd", Line: 14, Column: 1, Code: parser.ErrUnexpectedToken, Message: "予期しないEOFです"},
+	}
+
+	var buf bytes.Buffer
+	printParseErrors(&buf, errs, "")
+
+	want := "order.mmd:12:5: 解釈できない関連の矢印です: \"<-.-\"\n" +
+		"    \tA <-.- B\n" +
+		"    \t   ^\n" +
+		"order.mmd:14:1: 予期しないEOFです\n"
+	if got := buf.String(); got != want {
+		t.Errorf("printParseErrors() got = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	printParseErrors(&buf, errs[1:], "警告: ")
+	if got, want := buf.String(), "order.mmd:14:1: 警告: 予期しないEOFです\n"; got != want {
+		t.Errorf("printParseErrors() got = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package parser
+
+// Pos はソース上の位置（1始まりの行・列）を表現します
+type Pos struct {
+	Line   int
+	Column int
+}
+
+// Position はノードの位置を返します
+func (p Pos) Position() Pos {
+	return p
+}
+
+// Node はクラス図ASTのノードを表現します
+type Node interface {
+	Position() Pos
+}
+
+// DiagramNode はクラス図全体を表現するASTのルートです
+type DiagramNode struct {
+	Pos
+	Header     string
+	Statements []Node
+}
+
+// ClassNode はクラス宣言を表現します
+// Label は表示名（class Order["注文"]）で、指定がない場合は空文字列です
+type ClassNode struct {
+	Pos
+	Name        string
+	Label       string
+	Annotations []string
+	CSSClasses  []string
+	Members     []*MemberNode
+}
+
+// MemberNode はクラス本体に記述されたメンバー行を表現します
+type MemberNode struct {
+	Pos
+	Text string
+}
+
+// MemberDeclNode はクラス本体の外に書かれたメンバー宣言（Order : +cancel()）を表現します
+type MemberDeclNode struct {
+	Pos
+	Class string
+	Text  string
+}
+
+// AnnotationNode はクラス本体の外に書かれたアノテーション（<<interface>> Shape）を表現します
+type AnnotationNode struct {
+	Pos
+	Name  string
+	Class string
+}
+
+// RelationNode はクラス間の関連を表現します
+type RelationNode struct {
+	Pos
+	Source     string
+	Target     string
+	Arrow      string
+	SourceMult string
+	TargetMult string
+	Label      string
+}
+
+// DirectionNode は図の向き（direction LR）を表現します
+type DirectionNode struct {
+	Pos
+	Direction string
+}
+
+// StyleNode はクラス個別のスタイル指定（style Order fill:#f9f）を表現します
+type StyleNode struct {
+	Pos
+	Class      string
+	Properties string
+}
+
+// StyleClassNode はスタイルクラスの定義（classDef hot fill:red）を表現します
+type StyleClassNode struct {
+	Pos
+	Names      []string
+	Properties string
+}
+
+// CSSClassNode はクラスへのスタイルクラスの適用（cssClass "A,B" hot）を表現します
+type CSSClassNode struct {
+	Pos
+	Classes   []string
+	StyleName string
+}
+
+// LinkNode はクラスへのリンク（click Order href "url" "tooltip" / link Order "url"）を表現します
+type LinkNode struct {
+	Pos
+	Class   string
+	URL     string
+	Tooltip string
+}
+
+// CallbackNode はクラスへのJavaScriptコールバック（callback Order "fn" / click Order call fn()）を表現します
+type CallbackNode struct {
+	Pos
+	Class string
+}
+
+// DirectiveNode はディレクティブ（%%{init: {"theme": "dark"}}%%）を表現します
+// Text は %%{ と }%% に挟まれた部分です
+type DirectiveNode struct {
+	Pos
+	Text string
+}
+
+// FrontmatterNode は入力の先頭にあるYAMLフロントマター（--- で囲まれた title / config）を表現します
+// Text は区切り行を除いた本文で、Pos は本文の先頭行の位置です
+type FrontmatterNode struct {
+	Pos
+	Text string
+}
+
+// NoteNode は注釈を表現します
+// For が空の場合はどのクラスにも属さない浮いた注釈です
+type NoteNode struct {
+	Pos
+	For  string
+	Text string
+}
+
+// NamespaceNode は名前空間ブロックを表現します
+type NamespaceNode struct {
+	Pos
+	Name       string
+	Statements []Node
+}
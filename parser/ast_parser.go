@@ -0,0 +1,498 @@
+package parser
+
+import (
+	"strings"
+)
+
+// ASTParser はトークン列からクラス図のASTを構築します
+type ASTParser struct {
+	source *diagramSource
+	lexer  *Lexer
+	tok    Token
+	errors ParseErrors
+}
+
+// NewASTParser は新しいASTParserインスタンスを作成します
+// フロントマターとディレクティブは splitSource で分け、残りの本文をトークンに分解します
+func NewASTParser(input string) *ASTParser {
+	source, errs := splitSource(input)
+	return &ASTParser{
+		source: source,
+		lexer:  NewLexer(source.body),
+		errors: errs,
+	}
+}
+
+// Parse は入力全体を解析してASTを返します
+// 誤りのある文は読み飛ばして解析を続け、見つかったエラーをまとめてParseErrorsとして返します
+func (p *ASTParser) Parse() (*DiagramNode, error) {
+	diagram, errs := p.ParseAll()
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return diagram, nil
+}
+
+// ParseAll は入力全体を解析し、誤りのある文を除いたASTと見つかったエラーを返します
+func (p *ASTParser) ParseAll() (*DiagramNode, ParseErrors) {
+	diagram := &DiagramNode{Pos: Pos{Line: 1, Column: 1}}
+
+	p.next()
+	p.skipNewlines()
+
+	if p.source.frontmatter != nil {
+		diagram.Statements = append(diagram.Statements, p.source.frontmatter)
+	}
+	for _, directive := range p.source.directives {
+		diagram.Statements = append(diagram.Statements, directive)
+	}
+
+	if p.tok.Kind == TokenIdent && p.tok.Value == "classDiagram" {
+		// classDiagram-v2 の -v2 は識別子にならないため、行の残りとあわせて宣言とする
+		diagram.Header = p.tok.Value
+		end := p.tok.Pos.Column + len([]rune(p.tok.Value))
+		rest := p.restOfLine()
+		switch {
+		case rest.Value == "-v2" && rest.Pos.Column == end:
+			diagram.Header += rest.Value
+		case rest.Value != "":
+			p.synchronize(p.errorf(rest.Pos, ErrUnexpectedToken, "図の種類の宣言の後ろに余分な記述があります: %q", rest.Value))
+		}
+		if err := p.expectStatementEnd(); err != nil {
+			p.synchronize(err)
+		}
+	}
+
+	diagram.Statements = append(diagram.Statements, p.parseStatements(TokenEOF)...)
+
+	return diagram, p.errors
+}
+
+// parseStatements は指定した種類のトークンが現れるまで文を解析します
+// 解析できなかった文はエラーとして記録し、次の行から解析を再開します
+func (p *ASTParser) parseStatements(end TokenKind) []Node {
+	var statements []Node
+	for p.skipNewlines(); p.tok.Kind != end; p.skipNewlines() {
+		if p.tok.Kind == TokenEOF {
+			p.errors = append(p.errors, p.errorf(p.tok.Pos, ErrUnexpectedToken, "%sが必要ですが %s が見つかりました", end, p.tok))
+			return statements
+		}
+		stmt, err := p.parseStatement()
+		if err != nil {
+			p.synchronize(err)
+			continue
+		}
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// parseStatement は1つの文を解析します
+func (p *ASTParser) parseStatement() (Node, error) {
+	if p.tok.Kind == TokenAnnotation {
+		return p.parseAnnotation()
+	}
+	if p.tok.Kind != TokenIdent {
+		return nil, p.unexpected()
+	}
+
+	switch p.tok.Value {
+	case "class":
+		return p.parseClass()
+	case "namespace":
+		return p.parseNamespace()
+	case "note":
+		return p.parseNote()
+	case "direction":
+		return p.parseDirection()
+	case "style":
+		return p.parseStyle()
+	case "classDef":
+		return p.parseStyleClass()
+	case "cssClass":
+		return p.parseCSSClass()
+	case "click":
+		return p.parseClick()
+	case "link":
+		return p.parseLink()
+	case "callback":
+		return p.parseCallback()
+	}
+
+	first := p.tok
+	p.next()
+	if p.tok.Kind == TokenColon {
+		return p.parseMemberDecl(first)
+	}
+	return p.parseRelation(first)
+}
+
+// parseClass は class 宣言（class Name["表示名"]:::style <<annotation>> { ... }）を解析します
+func (p *ASTParser) parseClass() (*ClassNode, error) {
+	node := &ClassNode{Pos: p.tok.Pos}
+	p.next()
+
+	name, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Name = name.Value
+
+	if p.tok.Kind == TokenLBracket {
+		p.next()
+		label, err := p.expect(TokenString)
+		if err != nil {
+			return nil, err
+		}
+		node.Label = label.Value
+		if _, err := p.expect(TokenRBracket); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.tok.Kind == TokenCSSClass {
+		p.next()
+		styleName, err := p.expect(TokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		node.CSSClasses = append(node.CSSClasses, styleName.Value)
+	}
+
+	for p.tok.Kind == TokenAnnotation {
+		node.Annotations = append(node.Annotations, p.tok.Value)
+		p.next()
+	}
+
+	if p.tok.Kind == TokenLBrace {
+		p.next()
+		for p.tok.Kind == TokenMember {
+			node.Members = append(node.Members, &MemberNode{Pos: p.tok.Pos, Text: p.tok.Value})
+			p.next()
+		}
+		if _, err := p.expect(TokenRBrace); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, p.expectStatementEnd()
+}
+
+// parseNamespace は namespace ブロックを解析します（入れ子も可）
+func (p *ASTParser) parseNamespace() (*NamespaceNode, error) {
+	node := &NamespaceNode{Pos: p.tok.Pos}
+	p.next()
+
+	name, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Name = name.Value
+
+	if _, err := p.expect(TokenLBrace); err != nil {
+		return nil, err
+	}
+
+	node.Statements = p.parseStatements(TokenRBrace)
+	p.next()
+
+	return node, p.expectStatementEnd()
+}
+
+// parseDirection は図の向き（direction LR）を解析します
+func (p *ASTParser) parseDirection() (*DirectionNode, error) {
+	node := &DirectionNode{Pos: p.tok.Pos}
+	p.next()
+
+	direction, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Direction = direction.Value
+
+	return node, p.expectStatementEnd()
+}
+
+// parseStyle はクラス個別のスタイル指定（style Order fill:#f9f,stroke:#333）を解析します
+func (p *ASTParser) parseStyle() (*StyleNode, error) {
+	node := &StyleNode{Pos: p.tok.Pos}
+	p.next()
+
+	if p.tok.Kind != TokenIdent {
+		return nil, p.unexpected()
+	}
+	node.Class = p.tok.Value
+	node.Properties = p.restOfLine().Value
+
+	return node, p.expectStatementEnd()
+}
+
+// parseStyleClass はスタイルクラスの定義（classDef hot,warm fill:red）を解析します
+func (p *ASTParser) parseStyleClass() (*StyleClassNode, error) {
+	node := &StyleClassNode{Pos: p.tok.Pos}
+
+	rest := p.restOfLine()
+	names, properties, _ := strings.Cut(rest.Value, " ")
+	if names == "" {
+		return nil, p.errorf(rest.Pos, ErrMissingValue, "classDef のスタイルクラス名が記述されていません")
+	}
+	for _, name := range strings.Split(names, ",") {
+		node.Names = append(node.Names, strings.TrimSpace(name))
+	}
+	node.Properties = strings.TrimSpace(properties)
+
+	return node, p.expectStatementEnd()
+}
+
+// parseCSSClass はスタイルクラスの適用（cssClass "A,B" hot）を解析します
+func (p *ASTParser) parseCSSClass() (*CSSClassNode, error) {
+	node := &CSSClassNode{Pos: p.tok.Pos}
+	p.next()
+
+	classes, err := p.expect(TokenString)
+	if err != nil {
+		return nil, err
+	}
+	for _, class := range strings.Split(classes.Value, ",") {
+		node.Classes = append(node.Classes, strings.TrimSpace(class))
+	}
+
+	styleName, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.StyleName = styleName.Value
+
+	return node, p.expectStatementEnd()
+}
+
+// parseClick はクリック時の動作（click Order href "url" "tooltip" / click Order call fn()）を解析します
+func (p *ASTParser) parseClick() (Node, error) {
+	pos := p.tok.Pos
+	p.next()
+
+	class, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.Kind == TokenIdent && p.tok.Value == "href" {
+		p.next()
+		return p.parseLinkTarget(&LinkNode{Pos: pos, Class: class.Value})
+	}
+
+	// href 以外（call fn() やコールバック関数名）はJavaScriptのコールバックとして扱う
+	if p.tok.Kind == TokenNewline || p.tok.Kind == TokenEOF {
+		return nil, p.errorf(p.tok.Pos, ErrMissingValue, "%s のクリック時の動作が記述されていません", class.Value)
+	}
+	node := &CallbackNode{Pos: pos, Class: class.Value}
+	p.restOfLine()
+	return node, p.expectStatementEnd()
+}
+
+// parseLink はリンク（link Order "url" "tooltip"）を解析します
+func (p *ASTParser) parseLink() (*LinkNode, error) {
+	node := &LinkNode{Pos: p.tok.Pos}
+	p.next()
+
+	class, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Class = class.Value
+
+	return p.parseLinkTarget(node)
+}
+
+// parseLinkTarget はリンク先のURLと、省略可能なツールチップ・リンクターゲット（_blank）を解析します
+func (p *ASTParser) parseLinkTarget(node *LinkNode) (*LinkNode, error) {
+	url, err := p.expect(TokenString)
+	if err != nil {
+		return nil, err
+	}
+	node.URL = url.Value
+
+	if p.tok.Kind == TokenString {
+		node.Tooltip = p.tok.Value
+		p.next()
+	}
+	// PlantUMLにはリンクターゲットの指定がないため読み飛ばす
+	if p.tok.Kind == TokenIdent && strings.HasPrefix(p.tok.Value, "_") {
+		p.next()
+	}
+
+	return node, p.expectStatementEnd()
+}
+
+// parseCallback はコールバック（callback Order "fn" "tooltip"）を解析します
+func (p *ASTParser) parseCallback() (*CallbackNode, error) {
+	node := &CallbackNode{Pos: p.tok.Pos}
+	p.next()
+
+	class, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Class = class.Value
+
+	if _, err := p.expect(TokenString); err != nil {
+		return nil, err
+	}
+	if p.tok.Kind == TokenString {
+		p.next()
+	}
+
+	return node, p.expectStatementEnd()
+}
+
+// parseNote は注釈（note "text" / note for Class "text"）を解析します
+func (p *ASTParser) parseNote() (*NoteNode, error) {
+	node := &NoteNode{Pos: p.tok.Pos}
+	p.next()
+
+	if p.tok.Kind == TokenIdent && p.tok.Value == "for" {
+		p.next()
+		class, err := p.expect(TokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		node.For = class.Value
+	}
+
+	text, err := p.expect(TokenString)
+	if err != nil {
+		return nil, err
+	}
+	node.Text = text.Value
+
+	return node, p.expectStatementEnd()
+}
+
+// parseAnnotation はクラス本体の外のアノテーション（<<interface>> Shape）を解析します
+func (p *ASTParser) parseAnnotation() (*AnnotationNode, error) {
+	node := &AnnotationNode{Pos: p.tok.Pos, Name: p.tok.Value}
+	p.next()
+
+	class, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Class = class.Value
+
+	return node, p.expectStatementEnd()
+}
+
+// parseMemberDecl はクラス本体の外のメンバー宣言（Order : +cancel()）を解析します
+func (p *ASTParser) parseMemberDecl(class Token) (*MemberDeclNode, error) {
+	node := &MemberDeclNode{Pos: class.Pos, Class: class.Value}
+	p.next()
+
+	member, err := p.expect(TokenText)
+	if err != nil {
+		return nil, err
+	}
+	if member.Value == "" {
+		return nil, p.errorf(member.Pos, ErrMissingValue, "%s のメンバーが記述されていません", class.Value)
+	}
+	node.Text = member.Value
+
+	return node, p.expectStatementEnd()
+}
+
+// parseRelation は関連（A "1" --> "*" B : label）を解析します
+func (p *ASTParser) parseRelation(source Token) (*RelationNode, error) {
+	node := &RelationNode{Pos: source.Pos, Source: source.Value}
+
+	if p.tok.Kind == TokenString {
+		node.SourceMult = p.tok.Value
+		p.next()
+	}
+
+	arrow, err := p.expect(TokenArrow)
+	if err != nil {
+		return nil, err
+	}
+	node.Arrow = arrow.Value
+
+	if p.tok.Kind == TokenString {
+		node.TargetMult = p.tok.Value
+		p.next()
+	}
+
+	target, err := p.expect(TokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	node.Target = target.Value
+
+	if p.tok.Kind == TokenColon {
+		p.next()
+		node.Label = p.tok.Value
+		p.next()
+	}
+
+	return node, p.expectStatementEnd()
+}
+
+// expect は現在のトークンが指定した種類であれば読み進めて返します
+func (p *ASTParser) expect(kind TokenKind) (Token, error) {
+	tok := p.tok
+	if tok.Kind == TokenIllegal {
+		return tok, p.unexpected()
+	}
+	if tok.Kind != kind {
+		return tok, p.errorf(tok.Pos, ErrUnexpectedToken, "%sが必要ですが %s が見つかりました", kind, tok)
+	}
+	p.next()
+	return tok, nil
+}
+
+// expectStatementEnd は文の終わり（改行または入力の終端）を確認します
+func (p *ASTParser) expectStatementEnd() error {
+	switch p.tok.Kind {
+	case TokenNewline:
+		p.next()
+		return nil
+	case TokenEOF:
+		return nil
+	}
+	return p.unexpected()
+}
+
+// unexpected は現在のトークンが予期しないものであることを示すエラーを返します
+// 矢印の記号だけからなる不正なトークンは、解釈できない関連の矢印として報告します
+func (p *ASTParser) unexpected() error {
+	if p.tok.Kind == TokenIllegal && strings.Trim(p.tok.Value, "<>|*o-.") == "" && strings.ContainsAny(p.tok.Value, "-.") {
+		return p.errorf(p.tok.Pos, ErrUnknownArrow, "解釈できない関連の矢印です: %q", p.tok.Value)
+	}
+	return p.errorf(p.tok.Pos, ErrUnexpectedToken, "予期しない%sです", p.tok)
+}
+
+// synchronize はエラーを記録し、行末まで読み飛ばして次の文から解析を再開できるようにします
+func (p *ASTParser) synchronize(err error) {
+	p.errors = append(p.errors, asParseError(err, p.tok.Pos, ErrUnexpectedToken))
+	for p.tok.Kind != TokenNewline && p.tok.Kind != TokenEOF {
+		p.next()
+	}
+}
+
+func (p *ASTParser) errorf(pos Pos, code ErrorCode, format string, args ...interface{}) *ParseError {
+	return newParseError(pos, code, format, args...)
+}
+
+func (p *ASTParser) skipNewlines() {
+	for p.tok.Kind == TokenNewline {
+		p.next()
+	}
+}
+
+// restOfLine は現在のトークンの直後から行末までを読み取り、次のトークンに進みます
+func (p *ASTParser) restOfLine() Token {
+	rest := p.lexer.RestOfLine()
+	p.next()
+	return rest
+}
+
+func (p *ASTParser) next() {
+	p.tok = p.lexer.Next()
+}
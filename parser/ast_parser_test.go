@@ -0,0 +1,249 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestASTParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *DiagramNode
+		wantErr bool
+	}{
+		{
+			name:  "空の入力",
+			input: "",
+			want:  &DiagramNode{Pos: Pos{Line: 1, Column: 1}},
+		},
+		{
+			name: "クラスと関連",
+			input: `classDiagram
+class User {
+    +name: String
+}
+User "1" --> "*" Order : places`,
+			want: &DiagramNode{
+				Pos:    Pos{Line: 1, Column: 1},
+				Header: "classDiagram",
+				Statements: []Node{
+					&ClassNode{
+						Pos:  Pos{Line: 2, Column: 1},
+						Name: "User",
+						Members: []*MemberNode{
+							{Pos: Pos{Line: 3, Column: 5}, Text: "+name: String"},
+						},
+					},
+					&RelationNode{
+						Pos:        Pos{Line: 5, Column: 1},
+						Source:     "User",
+						Target:     "Order",
+						Arrow:      "-->",
+						SourceMult: "1",
+						TargetMult: "*",
+						Label:      "places",
+					},
+				},
+			},
+		},
+		{
+			name:  "本体のないクラス",
+			input: "classDiagram\nclass Foo\nclass Bar {}",
+			want: &DiagramNode{
+				Pos:    Pos{Line: 1, Column: 1},
+				Header: "classDiagram",
+				Statements: []Node{
+					&ClassNode{Pos: Pos{Line: 2, Column: 1}, Name: "Foo"},
+					&ClassNode{Pos: Pos{Line: 3, Column: 1}, Name: "Bar"},
+				},
+			},
+		},
+		{
+			name:  "アノテーション",
+			input: "<<interface>> Shape\nclass Service <<service>>",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&AnnotationNode{Pos: Pos{Line: 1, Column: 1}, Name: "interface", Class: "Shape"},
+					&ClassNode{Pos: Pos{Line: 2, Column: 1}, Name: "Service", Annotations: []string{"service"}},
+				},
+			},
+		},
+		{
+			name:  "本体の外のメンバー宣言",
+			input: "Order : +cancel() bool",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&MemberDeclNode{Pos: Pos{Line: 1, Column: 1}, Class: "Order", Text: "+cancel() bool"},
+				},
+			},
+		},
+		{
+			name:    "空のメンバー宣言",
+			input:   "Order :",
+			wantErr: true,
+		},
+		{
+			name:  "名前空間",
+			input: "namespace Billing {\n    class Invoice {\n        +id\n    }\n    namespace Tax {\n    }\n}",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&NamespaceNode{
+						Pos:  Pos{Line: 1, Column: 1},
+						Name: "Billing",
+						Statements: []Node{
+							&ClassNode{
+								Pos:     Pos{Line: 2, Column: 5},
+								Name:    "Invoice",
+								Members: []*MemberNode{{Pos: Pos{Line: 3, Column: 9}, Text: "+id"}},
+							},
+							&NamespaceNode{Pos: Pos{Line: 5, Column: 5}, Name: "Tax"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "注釈",
+			input: "note \"全体の注釈\"\nnote for Order \"1行目\\n2行目\"",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&NoteNode{Pos: Pos{Line: 1, Column: 1}, Text: "全体の注釈"},
+					&NoteNode{Pos: Pos{Line: 2, Column: 1}, For: "Order", Text: "1行目\\n2行目"},
+				},
+			},
+		},
+		{
+			name:  "図の向き",
+			input: "direction RL",
+			want: &DiagramNode{
+				Pos:        Pos{Line: 1, Column: 1},
+				Statements: []Node{&DirectionNode{Pos: Pos{Line: 1, Column: 1}, Direction: "RL"}},
+			},
+		},
+		{
+			name:  "表示名付きのクラス",
+			input: "class Order[\"注文\"] <<entity>>",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&ClassNode{Pos: Pos{Line: 1, Column: 1}, Name: "Order", Label: "注文", Annotations: []string{"entity"}},
+				},
+			},
+		},
+		{
+			name:  "スタイル指定",
+			input: "class Order:::hot\nstyle User fill:#f9f,stroke:#333\nclassDef hot,warm fill:#f96\ncssClass \"User, Order\" warm",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&ClassNode{Pos: Pos{Line: 1, Column: 1}, Name: "Order", CSSClasses: []string{"hot"}},
+					&StyleNode{Pos: Pos{Line: 2, Column: 1}, Class: "User", Properties: "fill:#f9f,stroke:#333"},
+					&StyleClassNode{Pos: Pos{Line: 3, Column: 1}, Names: []string{"hot", "warm"}, Properties: "fill:#f96"},
+					&CSSClassNode{Pos: Pos{Line: 4, Column: 1}, Classes: []string{"User", "Order"}, StyleName: "warm"},
+				},
+			},
+		},
+		{
+			name:    "名前のないスタイルクラス",
+			input:   "classDef",
+			wantErr: true,
+		},
+		{
+			name:  "リンクとコールバック",
+			input: "click Order href \"https://example.com\" \"注文\" _blank\nlink User \"https://example.com/user\"\ncallback Order \"showOrder\" \"詳細\"\nclick User call showUser(1)",
+			want: &DiagramNode{
+				Pos: Pos{Line: 1, Column: 1},
+				Statements: []Node{
+					&LinkNode{Pos: Pos{Line: 1, Column: 1}, Class: "Order", URL: "https://example.com", Tooltip: "注文"},
+					&LinkNode{Pos: Pos{Line: 2, Column: 1}, Class: "User", URL: "https://example.com/user"},
+					&CallbackNode{Pos: Pos{Line: 3, Column: 1}, Class: "Order"},
+					&CallbackNode{Pos: Pos{Line: 4, Column: 1}, Class: "User"},
+				},
+			},
+		},
+		{
+			name:    "動作のないクリック",
+			input:   "click Order\nclass Order",
+			wantErr: true,
+		},
+		{
+			name:    "URLのないリンク",
+			input:   "link Order",
+			wantErr: true,
+		},
+		{
+			name:  "ディレクティブとコメント",
+			input: "%% 説明\n%%{init: {'theme': 'forest'}}%%\nclassDiagram\n%% A -- B\nclass A",
+			want: &DiagramNode{
+				Pos:    Pos{Line: 1, Column: 1},
+				Header: "classDiagram",
+				Statements: []Node{
+					&DirectiveNode{Pos: Pos{Line: 2, Column: 1}, Text: "init: {'theme': 'forest'}"},
+					&ClassNode{Pos: Pos{Line: 5, Column: 1}, Name: "A"},
+				},
+			},
+		},
+		{
+			name:  "フロントマター",
+			input: "---\ntitle: 注文\n---\n%%{init: {}}%%\nclassDiagram\nclass A",
+			want: &DiagramNode{
+				Pos:    Pos{Line: 1, Column: 1},
+				Header: "classDiagram",
+				Statements: []Node{
+					&FrontmatterNode{Pos: Pos{Line: 2, Column: 1}, Text: "title: 注文\n"},
+					&DirectiveNode{Pos: Pos{Line: 4, Column: 1}, Text: "init: {}"},
+					&ClassNode{Pos: Pos{Line: 6, Column: 1}, Name: "A"},
+				},
+			},
+		},
+		{
+			name:  "classDiagram-v2の宣言",
+			input: "classDiagram-v2\nclass A",
+			want: &DiagramNode{
+				Pos:        Pos{Line: 1, Column: 1},
+				Header:     "classDiagram-v2",
+				Statements: []Node{&ClassNode{Pos: Pos{Line: 2, Column: 1}, Name: "A"}},
+			},
+		},
+		{
+			name:    "宣言の後ろの余分な記述",
+			input:   "classDiagram class A",
+			wantErr: true,
+		},
+		{
+			name:    "閉じていない表示名",
+			input:   "class Order[\"注文\"",
+			wantErr: true,
+		},
+		{
+			name:    "矢印のない行",
+			input:   "invalid syntax",
+			wantErr: true,
+		},
+		{
+			name:    "閉じていないクラス本体",
+			input:   "class Foo {\n+name: String",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewASTParser(tt.input).Parse()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
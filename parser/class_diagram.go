@@ -0,0 +1,118 @@
+package parser
+
+// ClassDiagram はクラス図全体のモデルを表現します
+// Classes は本体付きの宣言・本体のない宣言・関連だけに登場するクラスを、登場順に保持します
+// Direction は図の向き（TB / BT / LR / RL）で、指定がない場合は空文字列です
+// Title はフロントマターで指定された図のタイトルです
+// Config はフロントマターや %%{init}%% ディレクティブで指定された図全体の設定です
+// Warnings は変換できずに無視した指定についての警告です
+type ClassDiagram struct {
+	Direction     string
+	Title         string
+	Config        *DiagramConfig
+	Classes       []*ClassDefinition
+	Relationships []*Relationship
+	Notes         []*Note
+	StyleClasses  []*StyleClass
+	Warnings      ParseErrors
+	classIndex    map[string]*ClassDefinition
+	styleIndex    map[string]*StyleClass
+}
+
+// NewClassDiagram は新しいClassDiagramインスタンスを作成します
+func NewClassDiagram() *ClassDiagram {
+	return &ClassDiagram{
+		Config:        &DiagramConfig{ThemeVariables: make(map[string]string)},
+		Classes:       []*ClassDefinition{},
+		Relationships: []*Relationship{},
+		Notes:         []*Note{},
+		StyleClasses:  []*StyleClass{},
+		classIndex:    make(map[string]*ClassDefinition),
+		styleIndex:    make(map[string]*StyleClass),
+	}
+}
+
+// Class は名前に対応するクラス定義を返します（未登録の場合はnil）
+func (d *ClassDiagram) Class(name string) *ClassDefinition {
+	return d.classIndex[name]
+}
+
+// EnsureClass は名前に対応するクラス定義を返し、未登録であれば空のクラスとして登録します
+func (d *ClassDiagram) EnsureClass(name string) *ClassDefinition {
+	if classDef, ok := d.classIndex[name]; ok {
+		return classDef
+	}
+	classDef := &ClassDefinition{Name: name, Members: []string{}}
+	d.Classes = append(d.Classes, classDef)
+	d.classIndex[name] = classDef
+	return classDef
+}
+
+// AddClass はクラス定義を登録します
+// 同名のクラスが登録済みの場合は、アノテーションとメンバーを既存の定義に統合します
+func (d *ClassDiagram) AddClass(classDef *ClassDefinition) *ClassDefinition {
+	existing, ok := d.classIndex[classDef.Name]
+	if !ok {
+		d.Classes = append(d.Classes, classDef)
+		d.classIndex[classDef.Name] = classDef
+		return classDef
+	}
+
+	if existing.TypeParameters == "" {
+		existing.TypeParameters = classDef.TypeParameters
+	}
+	if existing.Label == "" {
+		existing.Label = classDef.Label
+	}
+	if existing.Namespace == "" {
+		existing.Namespace = classDef.Namespace
+	}
+	if existing.Style == nil {
+		existing.Style = classDef.Style
+	}
+	if existing.Link == "" {
+		existing.Link = classDef.Link
+		existing.Tooltip = classDef.Tooltip
+	}
+	existing.Annotations = append(existing.Annotations, classDef.Annotations...)
+	existing.CSSClasses = append(existing.CSSClasses, classDef.CSSClasses...)
+	existing.Members = append(existing.Members, classDef.Members...)
+	existing.IsEnum = existing.IsEnum || classDef.IsEnum
+	return existing
+}
+
+// AddRelationship は関連を登録し、両端のクラスをクラス一覧に加えます
+func (d *ClassDiagram) AddRelationship(rel *Relationship) {
+	d.EnsureClass(rel.Source)
+	d.EnsureClass(rel.Target)
+	d.Relationships = append(d.Relationships, rel)
+}
+
+// AddNote は注釈を登録し、注釈の対象クラスをクラス一覧に加えます
+func (d *ClassDiagram) AddNote(note *Note) {
+	if note.Class != "" {
+		d.EnsureClass(note.Class)
+	}
+	d.Notes = append(d.Notes, note)
+}
+
+// StyleClass は名前に対応するスタイルクラスを返します（未定義の場合はnil）
+func (d *ClassDiagram) StyleClass(name string) *StyleClass {
+	return d.styleIndex[name]
+}
+
+// EnsureStyleClass は名前に対応するスタイルクラスを返し、未定義であれば空のスタイルで登録します
+func (d *ClassDiagram) EnsureStyleClass(name string) *StyleClass {
+	if styleClass, ok := d.styleIndex[name]; ok {
+		return styleClass
+	}
+	styleClass := &StyleClass{Name: name, Style: &ClassStyle{}}
+	d.StyleClasses = append(d.StyleClasses, styleClass)
+	d.styleIndex[name] = styleClass
+	return styleClass
+}
+
+// AddWarning は警告を登録します
+func (d *ClassDiagram) AddWarning(pos Pos, code ErrorCode, format string, args ...interface{}) {
+	d.Warnings = append(d.Warnings, newParseError(pos, code, format, args...))
+}
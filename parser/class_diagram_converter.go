@@ -0,0 +1,379 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ClassDiagramConverter はMermaidのクラス図をPlantUMLのクラス図に変換するコンバーター
+type ClassDiagramConverter struct {
+	classParser        *ClassParser
+	relationshipParser *RelationshipParser
+	genericParser      *GenericTypeParser
+	styleParser        *StyleParser
+	settingsParser     *SettingsParser
+}
+
+// NewClassDiagramConverter は新しいClassDiagramConverterインスタンスを作成します
+func NewClassDiagramConverter() *ClassDiagramConverter {
+	return &ClassDiagramConverter{
+		classParser:        NewClassParser(),
+		relationshipParser: NewRelationshipParser(),
+		genericParser:      NewGenericTypeParser(),
+		styleParser:        NewStyleParser(),
+		settingsParser:     NewSettingsParser(),
+	}
+}
+
+// Convert はクラス図をPlantUML形式に変換し、変換時の警告とともに返します
+func (c *ClassDiagramConverter) Convert(input string, options ConvertOptions) (string, ParseErrors, error) {
+	diag := newDiagnostics(input, options.Mode)
+
+	diagram, errs := NewASTParser(input).ParseAll()
+	if err := diag.check(errs); err != nil {
+		return "", nil, err
+	}
+
+	model, errs := c.buildClassDiagram(diagram, options.Direction)
+	if err := diag.check(errs); err != nil {
+		return "", nil, err
+	}
+
+	warnings, err := diag.finish(model.Warnings)
+	if err != nil {
+		return "", nil, err
+	}
+
+	output, err := c.formatClassDiagram(model)
+	if err != nil {
+		return "", nil, err
+	}
+	return output, warnings, nil
+}
+
+// buildClassDiagram はASTからクラス図のモデルを構築します
+// direction は図の向きの上書き指定で、誤りのある文を除いたモデルと文ごとのエラーを返します
+func (c *ClassDiagramConverter) buildClassDiagram(diagram *DiagramNode, direction string) (*ClassDiagram, ParseErrors) {
+	model := NewClassDiagram()
+	annotations := []*AnnotationNode{}
+
+	errs := c.buildStatements(model, diagram.Statements, "", &annotations)
+
+	for _, node := range annotations {
+		name, _, err := c.genericParser.SplitName(node.Class)
+		if err != nil {
+			errs = append(errs, asParseError(err, node.Pos, ErrInvalidClass))
+			continue
+		}
+		c.classParser.ApplyAnnotation(model.EnsureClass(name), node.Name)
+	}
+
+	c.checkStyleClasses(model, diagram.Statements)
+
+	if direction != "" {
+		model.Direction = direction
+	}
+	for _, rel := range model.Relationships {
+		rel.LayoutDirection = directionLayouts[model.Direction].hint
+	}
+
+	return model, errs
+}
+
+// checkStyleClasses はクラスに指定されたスタイルクラスが classDef で定義されているかを確認します
+// classDef は参照より後に書かれることもあるため、すべての文を処理した後に確認します
+func (c *ClassDiagramConverter) checkStyleClasses(model *ClassDiagram, statements []Node) {
+	for _, stmt := range statements {
+		switch node := stmt.(type) {
+		case *NamespaceNode:
+			c.checkStyleClasses(model, node.Statements)
+		case *ClassNode:
+			for _, name := range node.CSSClasses {
+				if model.StyleClass(name) == nil {
+					model.AddWarning(node.Pos, ErrUndefinedStyleClass, "%s に未定義のスタイルクラスが指定されています: %s", node.Name, name)
+				}
+			}
+		case *CSSClassNode:
+			if model.StyleClass(node.StyleName) == nil {
+				model.AddWarning(node.Pos, ErrUndefinedStyleClass, "%s に未定義のスタイルクラスが指定されています: %s", strings.Join(node.Classes, ","), node.StyleName)
+			}
+		}
+	}
+}
+
+// buildStatements は文の並びをモデルに追加します
+// namespace は文が属する名前空間（トップレベルでは空文字列）です
+// 文ごとのエラーは記録して残りの文の処理を続け、最後にまとめて返します
+func (c *ClassDiagramConverter) buildStatements(model *ClassDiagram, statements []Node, namespace string, annotations *[]*AnnotationNode) ParseErrors {
+	var errs ParseErrors
+	for _, stmt := range statements {
+		if node, ok := stmt.(*NamespaceNode); ok {
+			// 入れ子の名前空間はドットで連結したパスで管理する
+			path := node.Name
+			if namespace != "" {
+				path = namespace + "." + node.Name
+			}
+			errs = append(errs, c.buildStatements(model, node.Statements, path, annotations)...)
+			continue
+		}
+
+		if err := c.buildStatement(model, stmt, namespace, annotations); err != nil {
+			errs = append(errs, asParseError(err, stmt.Position(), c.errorCode(stmt)))
+		}
+	}
+	return errs
+}
+
+// buildStatement は1つの文をモデルに追加します
+func (c *ClassDiagramConverter) buildStatement(model *ClassDiagram, stmt Node, namespace string, annotations *[]*AnnotationNode) error {
+	switch node := stmt.(type) {
+	case *ClassNode:
+		// クラスの内容を解析
+		classDef, err := c.classParser.BuildClassDefinition(node)
+		if err != nil {
+			return err
+		}
+		classDef.Namespace = namespace
+		model.AddClass(classDef)
+
+	case *MemberDeclNode:
+		// 本体の外のメンバー宣言は登場順に対象クラスへ追加する
+		name, _, err := c.genericParser.SplitName(node.Class)
+		if err != nil {
+			return err
+		}
+		classDef := model.EnsureClass(name)
+		if classDef.Namespace == "" {
+			classDef.Namespace = namespace
+		}
+		if err := c.classParser.AddMember(classDef, node.Text); err != nil {
+			return err
+		}
+
+	case *DirectionNode:
+		direction := strings.ToUpper(node.Direction)
+		if _, ok := directionLayouts[direction]; !ok {
+			return fmt.Errorf("サポートされていない図の向き: %s", node.Direction)
+		}
+		model.Direction = direction
+
+	case *StyleNode:
+		name, _, err := c.genericParser.SplitName(node.Class)
+		if err != nil {
+			return err
+		}
+		classDef := model.EnsureClass(name)
+		if classDef.Style == nil {
+			classDef.Style = &ClassStyle{}
+		}
+		c.styleParser.MergeStyle(classDef.Style, c.parseStyle(model, node.Pos, node.Properties))
+
+	case *StyleClassNode:
+		style := c.parseStyle(model, node.Pos, node.Properties)
+		for _, name := range node.Names {
+			c.styleParser.MergeStyle(model.EnsureStyleClass(name).Style, style)
+		}
+
+	case *CSSClassNode:
+		for _, class := range node.Classes {
+			name, _, err := c.genericParser.SplitName(class)
+			if err != nil {
+				return err
+			}
+			classDef := model.EnsureClass(name)
+			classDef.CSSClasses = append(classDef.CSSClasses, node.StyleName)
+		}
+
+	case *LinkNode:
+		name, _, err := c.genericParser.SplitName(node.Class)
+		if err != nil {
+			return err
+		}
+		classDef := model.EnsureClass(name)
+		classDef.Link = node.URL
+		classDef.Tooltip = node.Tooltip
+
+	case *FrontmatterNode:
+		title, warnings, err := c.settingsParser.ApplyFrontmatter(model.Config, node)
+		if err != nil {
+			return err
+		}
+		model.Title = title
+		model.Warnings = append(model.Warnings, warnings...)
+
+	case *DirectiveNode:
+		warnings, err := c.settingsParser.ApplyDirective(model.Config, node)
+		if err != nil {
+			return err
+		}
+		model.Warnings = append(model.Warnings, warnings...)
+
+	case *CallbackNode:
+		model.AddWarning(node.Pos, ErrUnsupported, "%s のコールバックはPlantUMLで表現できないため無視しました", node.Class)
+
+	case *NoteNode:
+		note, err := c.buildNote(node)
+		if err != nil {
+			return err
+		}
+		model.AddNote(note)
+
+	case *AnnotationNode:
+		// クラスの宣言より前に書かれることもあるため、最後にまとめて適用する
+		*annotations = append(*annotations, node)
+
+	case *RelationNode:
+		// 関連の処理
+		rel, err := c.relationshipParser.BuildRelationship(node)
+		if err != nil {
+			return err
+		}
+		model.AddRelationship(rel)
+	}
+	return nil
+}
+
+// errorCode は文の種類に対応するエラーの種類を返します
+func (c *ClassDiagramConverter) errorCode(stmt Node) ErrorCode {
+	switch stmt.(type) {
+	case *RelationNode:
+		return ErrInvalidRelation
+	case *DirectionNode:
+		return ErrInvalidDirection
+	case *DirectiveNode:
+		return ErrInvalidDirective
+	case *FrontmatterNode:
+		return ErrInvalidFrontmatter
+	}
+	return ErrInvalidClass
+}
+
+// parseStyle はスタイル指定を解析し、サポートしていない属性を警告として登録します
+func (c *ClassDiagramConverter) parseStyle(model *ClassDiagram, pos Pos, properties string) *ClassStyle {
+	style, warnings := c.styleParser.ParseStyle(properties)
+	for _, warning := range warnings {
+		model.AddWarning(pos, ErrUnsupported, "%s", warning)
+	}
+	return style
+}
+
+// noteLineBreak は注釈テキスト中の改行表記（\n と <br>）に一致します
+var noteLineBreak = regexp.MustCompile(`\\n|<br\s*/?>`)
+
+// buildNote は注釈ノードから注釈を構築します
+func (c *ClassDiagramConverter) buildNote(node *NoteNode) (*Note, error) {
+	note := &Note{Lines: noteLineBreak.Split(node.Text, -1)}
+	if node.For != "" {
+		name, _, err := c.genericParser.SplitName(node.For)
+		if err != nil {
+			return nil, err
+		}
+		note.Class = name
+	}
+	return note, nil
+}
+
+// formatClassDiagram はクラス図のモデルをPlantUML形式に変換します
+// package の中で宣言する前に関連からクラスが暗黙に作られないよう、クラス定義を先に出力します
+func (c *ClassDiagramConverter) formatClassDiagram(model *ClassDiagram) (string, error) {
+	var result strings.Builder
+	result.WriteString("@startuml\n")
+	result.WriteString(c.settingsParser.FormatSettings(model.Config, model.Title))
+
+	if directive := directionLayouts[model.Direction].directive; directive != "" {
+		result.WriteString(directive + "\n")
+	}
+
+	// スタイルクラスを出力（ステレオタイプ名は図に表示しない）
+	for _, styleClass := range model.StyleClasses {
+		result.WriteString(c.styleParser.FormatSkinparam(styleClass.Name, styleClass.Style))
+		if styleClass.Name != "default" {
+			result.WriteString(fmt.Sprintf("hide <<%s>> stereotype\n", styleClass.Name))
+		}
+	}
+
+	// クラス定義を出力
+	classes := make([]*ClassDefinition, len(model.Classes))
+	copy(classes, model.Classes)
+	sort.SliceStable(classes, func(i, j int) bool {
+		return classes[i].Name < classes[j].Name
+	})
+	c.formatNamespace(&result, classes, "", "")
+
+	// 関連を出力
+	for _, rel := range model.Relationships {
+		line, err := c.relationshipParser.FormatRelationship(rel)
+		if err != nil {
+			return "", err
+		}
+		result.WriteString(line + "\n")
+	}
+
+	// 注釈を出力
+	floating := 0
+	for _, note := range model.Notes {
+		if note.Class != "" {
+			result.WriteString(fmt.Sprintf("note right of %s\n", PlantUMLName(note.Class)))
+		} else {
+			floating++
+			result.WriteString(fmt.Sprintf("note as N%d\n", floating))
+		}
+		for _, line := range note.Lines {
+			result.WriteString("    " + line + "\n")
+		}
+		result.WriteString("end note\n")
+	}
+
+	result.WriteString("@enduml")
+	return result.String(), nil
+}
+
+// formatNamespace は名前空間に属するクラスを出力し、子の名前空間を package ブロックとして出力します
+func (c *ClassDiagramConverter) formatNamespace(result *strings.Builder, classes []*ClassDefinition, namespace string, indent string) {
+	children := []string{}
+	seen := make(map[string]bool)
+
+	for _, classDef := range classes {
+		if classDef.Namespace == namespace {
+			for _, line := range strings.SplitAfter(c.classParser.FormatClass(classDef), "\n") {
+				if line != "" {
+					result.WriteString(indent + line)
+				}
+			}
+			continue
+		}
+
+		child, ok := childNamespace(classDef.Namespace, namespace)
+		if ok && !seen[child] {
+			seen[child] = true
+			children = append(children, child)
+		}
+	}
+
+	sort.Strings(children)
+	for _, child := range children {
+		path := child
+		if namespace != "" {
+			path = namespace + "." + child
+		}
+		result.WriteString(fmt.Sprintf("%spackage %s {\n", indent, child))
+		c.formatNamespace(result, classes, path, indent+"    ")
+		result.WriteString(indent + "}\n")
+	}
+}
+
+// childNamespace は名前空間のパスが parent の配下にある場合、parent 直下の名前空間名を返します
+func childNamespace(path string, parent string) (string, bool) {
+	if parent != "" {
+		if !strings.HasPrefix(path, parent+".") {
+			return "", false
+		}
+		path = strings.TrimPrefix(path, parent+".")
+	}
+	if path == "" {
+		return "", false
+	}
+	return strings.SplitN(path, ".", 2)[0], true
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassDiagram_AddClass(t *testing.T) {
+	model := NewClassDiagram()
+	model.AddClass(&ClassDefinition{Name: "Order", Members: []string{}})
+	model.AddClass(&ClassDefinition{Name: "Customer", Members: []string{"+name : String"}})
+	model.AddClass(&ClassDefinition{
+		Name:        "Order",
+		Annotations: []string{"entity"},
+		Members:     []string{"+id : Integer"},
+	})
+
+	if got := len(model.Classes); got != 2 {
+		t.Fatalf("len(Classes) = %v, want 2", got)
+	}
+
+	want := &ClassDefinition{
+		Name:        "Order",
+		Annotations: []string{"entity"},
+		Members:     []string{"+id : Integer"},
+	}
+	if got := model.Class("Order"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Class() got = %v, want %v", got, want)
+	}
+}
+
+func TestClassDiagram_AddRelationship(t *testing.T) {
+	model := NewClassDiagram()
+	model.AddClass(&ClassDefinition{Name: "Order", Members: []string{"+id : Integer"}})
+	model.AddRelationship(&Relationship{Source: "Customer", Target: "Order", Type: "-->"})
+	model.AddRelationship(&Relationship{Source: "Order", Target: "Payment", Type: "..>"})
+
+	var got []string
+	for _, classDef := range model.Classes {
+		got = append(got, classDef.Name)
+	}
+
+	want := []string{"Order", "Customer", "Payment"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Classes got = %v, want %v", got, want)
+	}
+
+	if got := model.Class("Order").Members; !reflect.DeepEqual(got, []string{"+id : Integer"}) {
+		t.Errorf("関連によって既存のクラス定義が変更されています: %v", got)
+	}
+}
+
+func TestClassDiagram_AddNote(t *testing.T) {
+	model := NewClassDiagram()
+	model.AddNote(&Note{Lines: []string{"全体の注釈"}})
+	model.AddNote(&Note{Class: "Order", Lines: []string{"注文の注釈"}})
+
+	if got := len(model.Notes); got != 2 {
+		t.Fatalf("len(Notes) = %v, want 2", got)
+	}
+	if model.Class("Order") == nil {
+		t.Errorf("注釈の対象クラスが登録されていません")
+	}
+	if got := len(model.Classes); got != 1 {
+		t.Errorf("len(Classes) = %v, want 1", got)
+	}
+}
@@ -8,92 +8,293 @@ import (
 
 // ClassParser はクラス定義の解析を担当します
 type ClassParser struct {
-	memberPattern *regexp.Regexp
+	methodPattern     *regexp.Regexp
+	namedTypePattern  *regexp.Regexp
+	typedNamePattern  *regexp.Regexp
+	bareMemberPattern *regexp.Regexp
+	annotationPattern *regexp.Regexp
+	genericParser     *GenericTypeParser
+	styleParser       *StyleParser
 }
 
 // NewClassParser は新しいClassParserインスタンスを作成します
 func NewClassParser() *ClassParser {
 	return &ClassParser{
-		memberPattern: regexp.MustCompile(`\s*([+\-#~])?(?:(\w+):\s*(\w+(?:~[^~]+~)?)|(\w+)(?:\((.*?)\))?)`)}
+		// +find(id) Order[] / +void register() / +getName(): String
+		methodPattern: regexp.MustCompile(`^([+\-#~])?\s*(.*?)\s*\((.*)\)\s*:?\s*(.*)$`),
+		// +name: String
+		namedTypePattern: regexp.MustCompile(`^([+\-#~])?\s*([^\s:]+)\s*:\s*(.+)$`),
+		// +String name
+		typedNamePattern: regexp.MustCompile(`^([+\-#~])?\s*(.+?)\s+(\S+)$`),
+		// +name
+		bareMemberPattern: regexp.MustCompile(`^([+\-#~])?\s*(\S+)$`),
+		// <<interface>>
+		annotationPattern: regexp.MustCompile(`^<<\s*(.+?)\s*>>$`),
+		genericParser:     NewGenericTypeParser(),
+		styleParser:       NewStyleParser(),
+	}
 }
 
-// ParseClassContent はクラス定義の内容を解析します
-func (p *ClassParser) ParseClassContent(lines []string, startIndex int) (int, *ClassDefinition, error) {
+// BuildClassDefinition はASTのクラスノードからクラス定義を構築します
+func (p *ClassParser) BuildClassDefinition(node *ClassNode) (*ClassDefinition, error) {
 	classDef := &ClassDefinition{
 		Members: []string{},
 		IsEnum:  false,
 	}
 
-	currentIndex := startIndex
+	if node.Name != "" {
+		name, typeParams, err := p.genericParser.SplitName(node.Name)
+		if err != nil {
+			return nil, err
+		}
+		classDef.Name = name
+		classDef.TypeParameters = typeParams
+	}
+	classDef.Label = node.Label
+	classDef.CSSClasses = node.CSSClasses
+
+	for _, annotation := range node.Annotations {
+		p.ApplyAnnotation(classDef, annotation)
+	}
 
-	for currentIndex < len(lines) {
-		line := strings.TrimSpace(lines[currentIndex])
-		if line == "}" {
-			break
+	for _, memberNode := range node.Members {
+		if err := p.AddMember(classDef, memberNode.Text); err != nil {
+			return nil, err
 		}
+	}
 
-		if line == "<<enumeration>>" {
-			classDef.IsEnum = true
-			classDef.Members = append(classDef.Members, line)
-			currentIndex++
-			continue
+	return classDef, nil
+}
+
+// AddMember はメンバー行を解析してクラス定義の末尾に追加します
+// クラス本体の行と、本体の外のメンバー宣言（Order : +cancel()）の両方で使用します
+func (p *ClassParser) AddMember(classDef *ClassDefinition, line string) error {
+	if matches := p.annotationPattern.FindStringSubmatch(line); matches != nil {
+		p.ApplyAnnotation(classDef, matches[1])
+		return nil
+	}
+
+	if classDef.IsEnum {
+		// 列挙型の値は単純に追加
+		classDef.Members = append(classDef.Members, line)
+	} else if member := p.parseMember(line); member != nil {
+		if err := p.convertGenerics(member); err != nil {
+			return err
 		}
+		classDef.Members = append(classDef.Members, p.formatMember(member))
+	}
+	return nil
+}
+
+// ApplyAnnotation はクラス定義にアノテーションを追加します
+func (p *ClassParser) ApplyAnnotation(classDef *ClassDefinition, annotation string) {
+	classDef.Annotations = append(classDef.Annotations, annotation)
+	switch strings.ToLower(annotation) {
+	case "enumeration", "enum":
+		classDef.IsEnum = true
+	}
+}
+
+// classKeywords はPlantUMLの宣言キーワードに対応するアノテーションです
+var classKeywords = map[string]string{
+	"interface":   "interface",
+	"abstract":    "abstract class",
+	"enumeration": "enum",
+	"enum":        "enum",
+	"annotation":  "annotation",
+}
 
-		if classDef.IsEnum {
-			// 列挙型の値は単純に追加
-			if line != "" && line != "<<enumeration>>" {
-				classDef.Members = append(classDef.Members, line)
-			}
-		} else if line == "<<interface>>" || line == "<<abstract>>" {
-			// インターフェースと抽象クラスのステレオタイプを追加
-			classDef.Members = append(classDef.Members, line)
-		} else if matches := p.memberPattern.FindStringSubmatch(line); matches != nil {
-			member := p.parseMember(matches)
-			if member != nil {
-				classDef.Members = append(classDef.Members, p.formatMember(member))
-			}
+// FormatClass はクラス定義をPlantUML形式の宣言にフォーマットします
+// スタイルクラス（:::hot）はステレオタイプ、リンクと個別のスタイルはその後ろに付けます
+// メンバーがない場合は本体の括弧を省略します
+func (p *ClassParser) FormatClass(classDef *ClassDefinition) string {
+	keyword, stereotypes := p.classKeyword(classDef)
+
+	header := PlantUMLName(classDef.Name)
+	if classDef.Label != "" {
+		header = fmt.Sprintf("\"%s\" as %s", classDef.Label, header)
+	}
+	if classDef.TypeParameters != "" {
+		header += "<" + classDef.TypeParameters + ">"
+	}
+	for _, stereotype := range append(stereotypes, classDef.CSSClasses...) {
+		header += fmt.Sprintf(" <<%s>>", stereotype)
+	}
+	if classDef.Link != "" {
+		header += " " + p.formatLink(classDef.Link, classDef.Tooltip)
+	}
+	if classDef.Style != nil {
+		if color := p.styleParser.FormatInlineColor(classDef.Style); color != "" {
+			header += " " + color
 		}
-		currentIndex++
 	}
 
-	return currentIndex, classDef, nil
+	if len(classDef.Members) == 0 {
+		return fmt.Sprintf("%s %s\n", keyword, header)
+	}
+
+	var block strings.Builder
+	block.WriteString(fmt.Sprintf("%s %s {\n", keyword, header))
+	for _, member := range classDef.Members {
+		block.WriteString(fmt.Sprintf("    %s\n", member))
+	}
+	block.WriteString("}\n")
+	return block.String()
+}
+
+// plantUMLIdentPattern はPlantUMLでそのまま名前として使える文字列に一致します
+var plantUMLIdentPattern = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+
+// PlantUMLName はクラス名をPlantUMLで参照できる形にします
+// 空白や記号を含む名前（バッククォートで囲まれた名前など）はダブルクォートで囲みます
+func PlantUMLName(name string) string {
+	if plantUMLIdentPattern.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("\"%s\"", name)
+}
+
+// formatLink はリンクをPlantUMLのハイパーリンク（[[url{tooltip}]]）にフォーマットします
+func (p *ClassParser) formatLink(url, tooltip string) string {
+	if tooltip == "" {
+		return fmt.Sprintf("[[%s]]", url)
+	}
+	return fmt.Sprintf("[[%s{%s}]]", url, tooltip)
+}
+
+// classKeyword はクラス定義の宣言キーワードと、キーワードにならないステレオタイプを返します
+func (p *ClassParser) classKeyword(classDef *ClassDefinition) (string, []string) {
+	keyword := "class"
+	if classDef.IsEnum {
+		keyword = "enum"
+	}
+
+	var stereotypes []string
+	for _, annotation := range classDef.Annotations {
+		if kw, ok := classKeywords[strings.ToLower(annotation)]; ok {
+			keyword = kw
+			continue
+		}
+		stereotypes = append(stereotypes, annotation)
+	}
+	return keyword, stereotypes
 }
 
 // parseMember はメンバー定義を解析します
-func (p *ClassParser) parseMember(matches []string) *ClassMember {
-	visibility := matches[1]
-	if visibility == "" {
-		visibility = "+"
+// 属性は「+Type name」と「+name: Type」、メソッドは「+name(params) Return」と
+// 「+Return name(params)」の両方の書き方を受け付けます
+func (p *ClassParser) parseMember(line string) *ClassMember {
+	line, classifier := p.splitClassifier(line)
+	member := p.parseMemberBody(line)
+	if member != nil {
+		member.IsStatic = classifier == "$"
+		member.IsAbstract = classifier == "*"
+	}
+	return member
+}
+
+// splitClassifier は行末の分類子（静的メンバーの $、抽象メンバーの *）を切り離します
+func (p *ClassParser) splitClassifier(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if strings.HasSuffix(line, "$") || strings.HasSuffix(line, "*") {
+		return strings.TrimSpace(line[:len(line)-1]), line[len(line)-1:]
 	}
+	return line, ""
+}
 
-	if matches[2] != "" && matches[3] != "" {
-		// 属性の場合
+// parseMemberBody は分類子を除いたメンバー定義を解析します
+func (p *ClassParser) parseMemberBody(line string) *ClassMember {
+	if matches := p.methodPattern.FindStringSubmatch(line); matches != nil && matches[2] != "" {
+		// メソッドの場合
+		member := &ClassMember{
+			Visibility: p.visibility(matches[1]),
+			Name:       matches[2],
+			Parameters: strings.TrimSpace(matches[3]),
+			ReturnType: strings.TrimSpace(matches[4]),
+			IsMethod:   true,
+		}
+		if fields := strings.Fields(member.Name); len(fields) > 1 && member.ReturnType == "" {
+			// 戻り値の型が名前の前に書かれている場合
+			member.ReturnType = strings.Join(fields[:len(fields)-1], " ")
+			member.Name = fields[len(fields)-1]
+		}
+		return member
+	}
+
+	if matches := p.namedTypePattern.FindStringSubmatch(line); matches != nil {
+		// 属性（name: Type）の場合
 		return &ClassMember{
-			Visibility: visibility,
+			Visibility: p.visibility(matches[1]),
 			Name:       matches[2],
-			Type:       matches[3],
+			Type:       strings.TrimSpace(matches[3]),
 			IsMethod:   false,
 		}
-	} else if matches[4] != "" {
-		// メソッドの場合
+	}
+
+	if matches := p.typedNamePattern.FindStringSubmatch(line); matches != nil {
+		// 属性（Type name）の場合
 		return &ClassMember{
-			Visibility: visibility,
-			Name:       matches[4],
-			Parameters: matches[5],
-			IsMethod:   true,
+			Visibility: p.visibility(matches[1]),
+			Name:       matches[3],
+			Type:       matches[2],
+			IsMethod:   false,
 		}
 	}
 
+	if matches := p.bareMemberPattern.FindStringSubmatch(line); matches != nil {
+		// 型のない属性の場合
+		return &ClassMember{
+			Visibility: p.visibility(matches[1]),
+			Name:       matches[2],
+			IsMethod:   false,
+		}
+	}
+
+	return nil
+}
+
+// convertGenerics はメンバーの型・パラメータ・戻り値のジェネリック型をPlantUML表記に変換します
+func (p *ClassParser) convertGenerics(member *ClassMember) error {
+	for _, field := range []*string{&member.Type, &member.Parameters, &member.ReturnType} {
+		converted, err := p.genericParser.ToPlantUML(*field)
+		if err != nil {
+			return err
+		}
+		*field = converted
+	}
 	return nil
 }
 
-// formatMember はメンバーを文字列形式にフォーマットします
+// visibility は可視性修飾子を返します（省略時はpublic）
+func (p *ClassParser) visibility(modifier string) string {
+	if modifier == "" {
+		return "+"
+	}
+	return modifier
+}
+
+// formatMember はメンバーをPlantUML形式にフォーマットします
 func (p *ClassParser) formatMember(member *ClassMember) string {
+	switch {
+	case member.IsStatic:
+		return "{static} " + p.formatMemberBody(member)
+	case member.IsAbstract:
+		return "{abstract} " + p.formatMemberBody(member)
+	}
+	return p.formatMemberBody(member)
+}
+
+// formatMemberBody は修飾子を除いたメンバーをフォーマットします
+func (p *ClassParser) formatMemberBody(member *ClassMember) string {
 	if member.IsMethod {
-		if member.Parameters != "" {
-			return fmt.Sprintf("%s%s(%s)", member.Visibility, member.Name, member.Parameters)
+		method := fmt.Sprintf("%s%s(%s)", member.Visibility, member.Name, member.Parameters)
+		if member.ReturnType != "" {
+			return fmt.Sprintf("%s : %s", method, member.ReturnType)
 		}
-		return fmt.Sprintf("%s%s()", member.Visibility, member.Name)
+		return method
+	}
+	if member.Type != "" {
+		return fmt.Sprintf("%s%s : %s", member.Visibility, member.Name, member.Type)
 	}
-	return fmt.Sprintf("%s%s: %s", member.Visibility, member.Name, member.Type)
+	return fmt.Sprintf("%s%s", member.Visibility, member.Name)
 }
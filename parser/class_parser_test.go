@@ -2,17 +2,16 @@ package parser
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
-func TestClassParser_ParseClassContent(t *testing.T) {
+func TestClassParser_BuildClassDefinition(t *testing.T) {
 	tests := []struct {
-		name      string
-		lines     []string
-		startLine int
-		want      *ClassDefinition
-		wantIndex int
-		wantErr   bool
+		name    string
+		lines   []string
+		want    *ClassDefinition
+		wantErr bool
 	}{
 		{
 			name: "通常のクラス定義",
@@ -20,19 +19,16 @@ func TestClassParser_ParseClassContent(t *testing.T) {
 				"+name: String",
 				"+age: Integer",
 				"+getName()",
-				"}",
 			},
-			startLine: 0,
 			want: &ClassDefinition{
+				Name: "Order",
 				Members: []string{
-					"+name: String",
-					"+age: Integer",
+					"+name : String",
+					"+age : Integer",
 					"+getName()",
 				},
 				IsEnum: false,
 			},
-			wantIndex: 3,
-			wantErr:   false,
 		},
 		{
 			name: "列挙型の定義",
@@ -41,20 +37,17 @@ func TestClassParser_ParseClassContent(t *testing.T) {
 				"PENDING",
 				"ACTIVE",
 				"COMPLETED",
-				"}",
 			},
-			startLine: 0,
 			want: &ClassDefinition{
+				Name:        "Order",
+				Annotations: []string{"enumeration"},
 				Members: []string{
-					"<<enumeration>>",
 					"PENDING",
 					"ACTIVE",
 					"COMPLETED",
 				},
 				IsEnum: true,
 			},
-			wantIndex: 4,
-			wantErr:   false,
 		},
 		{
 			name: "インターフェース定義",
@@ -62,19 +55,16 @@ func TestClassParser_ParseClassContent(t *testing.T) {
 				"<<interface>>",
 				"+process()",
 				"+cancel()",
-				"}",
 			},
-			startLine: 0,
 			want: &ClassDefinition{
+				Name:        "Order",
+				Annotations: []string{"interface"},
 				Members: []string{
-					"<<interface>>",
 					"+process()",
 					"+cancel()",
 				},
 				IsEnum: false,
 			},
-			wantIndex: 3,
-			wantErr:   false,
 		},
 		{
 			name: "可視性修飾子の組み合わせ",
@@ -83,75 +73,156 @@ func TestClassParser_ParseClassContent(t *testing.T) {
 				"-private: Integer",
 				"#protected: Double",
 				"~package: Boolean",
-				"}",
 			},
-			startLine: 0,
 			want: &ClassDefinition{
+				Name: "Order",
 				Members: []string{
-					"+public: String",
-					"-private: Integer",
-					"#protected: Double",
-					"~package: Boolean",
+					"+public : String",
+					"-private : Integer",
+					"#protected : Double",
+					"~package : Boolean",
 				},
 				IsEnum: false,
 			},
-			wantIndex: 4,
-			wantErr:   false,
 		},
 		{
 			name: "パラメータ付きメソッド",
 			lines: []string{
 				"+calculate(Double x, Double y)",
 				"-process(String data)",
-				"}",
 			},
-			startLine: 0,
 			want: &ClassDefinition{
+				Name: "Order",
 				Members: []string{
 					"+calculate(Double x, Double y)",
 					"-process(String data)",
 				},
 				IsEnum: false,
 			},
-			wantIndex: 2,
-			wantErr:   false,
 		},
 		{
 			name: "ジェネリック型",
 			lines: []string{
 				"+items: List~String~",
 				"+counts: Map~String,Integer~",
-				"}",
 			},
-			startLine: 0,
 			want: &ClassDefinition{
+				Name: "Order",
 				Members: []string{
-					"+items: List~String~",
-					"+counts: Map~String,Integer~",
+					"+items : List<String>",
+					"+counts : Map<String,Integer>",
 				},
 				IsEnum: false,
 			},
-			wantIndex: 2,
-			wantErr:   false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewClassParser()
-			gotIndex, got, err := p.ParseClassContent(tt.lines, tt.startLine)
+			input := "class Order {\n" + strings.Join(tt.lines, "\n") + "\n}"
+			diagram, err := NewASTParser(input).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
 
+			got, err := NewClassParser().BuildClassDefinition(diagram.Statements[0].(*ClassNode))
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ParseClassContent() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("BuildClassDefinition() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildClassDefinition() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
 
-			if gotIndex != tt.wantIndex {
-				t.Errorf("ParseClassContent() gotIndex = %v, want %v", gotIndex, tt.wantIndex)
+func TestClassParser_FormatClass(t *testing.T) {
+	tests := []struct {
+		name     string
+		classDef *ClassDefinition
+		want     string
+	}{
+		{
+			name:     "通常のクラス",
+			classDef: &ClassDefinition{Name: "User", Members: []string{"+name : String"}},
+			want:     "class User {\n    +name : String\n}\n",
+		},
+		{
+			name:     "インターフェース",
+			classDef: &ClassDefinition{Name: "Shape", Annotations: []string{"interface"}, Members: []string{"+draw()"}},
+			want:     "interface Shape {\n    +draw()\n}\n",
+		},
+		{
+			name:     "抽象クラス",
+			classDef: &ClassDefinition{Name: "Base", Annotations: []string{"Abstract"}},
+			want:     "abstract class Base\n",
+		},
+		{
+			name:     "列挙型",
+			classDef: &ClassDefinition{Name: "Status", Annotations: []string{"enumeration"}, Members: []string{"ACTIVE"}, IsEnum: true},
+			want:     "enum Status {\n    ACTIVE\n}\n",
+		},
+		{
+			name:     "独自ステレオタイプとジェネリック",
+			classDef: &ClassDefinition{Name: "Repository", TypeParameters: "T", Annotations: []string{"service", "interface"}},
+			want:     "interface Repository<T> <<service>>\n",
+		},
+		{
+			name:     "表示名と空白を含む名前",
+			classDef: &ClassDefinition{Name: "Order Item", Label: "注文明細"},
+			want:     "class \"注文明細\" as \"Order Item\"\n",
+		},
+		{
+			name:     "アノテーション型",
+			classDef: &ClassDefinition{Name: "Audited", Annotations: []string{"annotation"}},
+			want:     "annotation Audited\n",
+		},
+		{
+			name:     "アノテーションのない列挙型",
+			classDef: &ClassDefinition{Name: "Color", Members: []string{"RED", "GREEN"}, IsEnum: true},
+			want:     "enum Color {\n    RED\n    GREEN\n}\n",
+		},
+		{
+			name:     "リンクとスタイル",
+			classDef: &ClassDefinition{Name: "Order", CSSClasses: []string{"hot"}, Link: "https://example.com/order", Tooltip: "注文", Style: &ClassStyle{Fill: "#f9f"}},
+			want:     "class Order <<hot>> [[https://example.com/order{注文}]] #back:f9f\n",
+		},
+		{
+			name:     "ツールチップのないリンク",
+			classDef: &ClassDefinition{Name: "Order", Link: "https://example.com/order"},
+			want:     "class Order [[https://example.com/order]]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewClassParser()
+			got := p.FormatClass(tt.classDef)
+
+			if got != tt.want {
+				t.Errorf("FormatClass() got = %v, want %v", got, tt.want)
 			}
+		})
+	}
+}
 
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ParseClassContent() got = %v, want %v", got, tt.want)
+func TestPlantUMLName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "英数字", in: "Order_2", want: "Order_2"},
+		{name: "日本語", in: "注文", want: "注文"},
+		{name: "空白を含む名前", in: "Order Item", want: `"Order Item"`},
+		{name: "記号を含む名前", in: "Animal!", want: `"Animal!"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlantUMLName(tt.in); got != tt.want {
+				t.Errorf("PlantUMLName() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -159,13 +230,13 @@ func TestClassParser_ParseClassContent(t *testing.T) {
 
 func TestClassParser_ParseMember(t *testing.T) {
 	tests := []struct {
-		name    string
-		matches []string
-		want    *ClassMember
+		name string
+		line string
+		want *ClassMember
 	}{
 		{
-			name:    "属性（デフォルト可視性）",
-			matches: []string{"name: String", "", "name", "String", "", ""},
+			name: "属性（デフォルト可視性）",
+			line: "name: String",
 			want: &ClassMember{
 				Visibility: "+",
 				Name:       "name",
@@ -174,8 +245,8 @@ func TestClassParser_ParseMember(t *testing.T) {
 			},
 		},
 		{
-			name:    "属性（private）",
-			matches: []string{"-count: Integer", "-", "count", "Integer", "", ""},
+			name: "属性（private）",
+			line: "-count: Integer",
 			want: &ClassMember{
 				Visibility: "-",
 				Name:       "count",
@@ -184,8 +255,37 @@ func TestClassParser_ParseMember(t *testing.T) {
 			},
 		},
 		{
-			name:    "メソッド（パラメータなし）",
-			matches: []string{"+process()", "+", "", "", "process", ""},
+			name: "属性（型が先）",
+			line: "+String name",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "name",
+				Type:       "String",
+				IsMethod:   false,
+			},
+		},
+		{
+			name: "属性（ジェネリック型が先）",
+			line: "-List~int~ ids",
+			want: &ClassMember{
+				Visibility: "-",
+				Name:       "ids",
+				Type:       "List~int~",
+				IsMethod:   false,
+			},
+		},
+		{
+			name: "属性（型なし）",
+			line: "#note",
+			want: &ClassMember{
+				Visibility: "#",
+				Name:       "note",
+				IsMethod:   false,
+			},
+		},
+		{
+			name: "メソッド（パラメータなし）",
+			line: "+process()",
 			want: &ClassMember{
 				Visibility: "+",
 				Name:       "process",
@@ -194,8 +294,8 @@ func TestClassParser_ParseMember(t *testing.T) {
 			},
 		},
 		{
-			name:    "メソッド（パラメータあり）",
-			matches: []string{"+calculate(Double x, Double y)", "+", "", "", "calculate", "Double x, Double y"},
+			name: "メソッド（パラメータあり）",
+			line: "+calculate(Double x, Double y)",
 			want: &ClassMember{
 				Visibility: "+",
 				Name:       "calculate",
@@ -203,12 +303,85 @@ func TestClassParser_ParseMember(t *testing.T) {
 				IsMethod:   true,
 			},
 		},
+		{
+			name: "メソッド（戻り値の型）",
+			line: "+getTotal() BigDecimal",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "getTotal",
+				ReturnType: "BigDecimal",
+				IsMethod:   true,
+			},
+		},
+		{
+			name: "メソッド（配列の戻り値）",
+			line: "+find(id) Order[]",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "find",
+				Parameters: "id",
+				ReturnType: "Order[]",
+				IsMethod:   true,
+			},
+		},
+		{
+			name: "メソッド（コロン付きの戻り値）",
+			line: "+getName(): String",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "getName",
+				ReturnType: "String",
+				IsMethod:   true,
+			},
+		},
+		{
+			name: "メソッド（戻り値の型が先）",
+			line: "+void register()",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "register",
+				ReturnType: "void",
+				IsMethod:   true,
+			},
+		},
+		{
+			name: "静的メソッド",
+			line: "+count()$",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "count",
+				IsMethod:   true,
+				IsStatic:   true,
+			},
+		},
+		{
+			name: "戻り値付きの抽象メソッド",
+			line: "+area() double*",
+			want: &ClassMember{
+				Visibility: "+",
+				Name:       "area",
+				ReturnType: "double",
+				IsMethod:   true,
+				IsAbstract: true,
+			},
+		},
+		{
+			name: "静的属性",
+			line: "-String instance$",
+			want: &ClassMember{
+				Visibility: "-",
+				Name:       "instance",
+				Type:       "String",
+				IsMethod:   false,
+				IsStatic:   true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := NewClassParser()
-			got := p.parseMember(tt.matches)
+			got := p.parseMember(tt.line)
 
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("parseMember() got = %v, want %v", got, tt.want)
@@ -231,7 +404,16 @@ func TestClassParser_FormatMember(t *testing.T) {
 				Type:       "String",
 				IsMethod:   false,
 			},
-			want: "+name: String",
+			want: "+name : String",
+		},
+		{
+			name: "型のない属性",
+			member: &ClassMember{
+				Visibility: "-",
+				Name:       "note",
+				IsMethod:   false,
+			},
+			want: "-note",
 		},
 		{
 			name: "メソッド（パラメータなし）",
@@ -252,6 +434,37 @@ func TestClassParser_FormatMember(t *testing.T) {
 			},
 			want: "#calculate(Double x, Double y)",
 		},
+		{
+			name: "メソッド（戻り値あり）",
+			member: &ClassMember{
+				Visibility: "+",
+				Name:       "find",
+				Parameters: "id",
+				ReturnType: "Order[]",
+				IsMethod:   true,
+			},
+			want: "+find(id) : Order[]",
+		},
+		{
+			name: "静的属性",
+			member: &ClassMember{
+				Visibility: "-",
+				Name:       "instance",
+				Type:       "String",
+				IsStatic:   true,
+			},
+			want: "{static} -instance : String",
+		},
+		{
+			name: "抽象メソッド",
+			member: &ClassMember{
+				Visibility: "+",
+				Name:       "draw",
+				IsMethod:   true,
+				IsAbstract: true,
+			},
+			want: "{abstract} +draw()",
+		},
 	}
 
 	for _, tt := range tests {
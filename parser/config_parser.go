@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ConfigParser はMermaidの設定（%%{init: {...}}%% ディレクティブ）の解析を担当します
+type ConfigParser struct {
+	unquotedKeyPattern *regexp.Regexp
+}
+
+// NewConfigParser は新しいConfigParserインスタンスを作成します
+func NewConfigParser() *ConfigParser {
+	return &ConfigParser{
+		unquotedKeyPattern: regexp.MustCompile(`([{,]\s*)([A-Za-z_][\w-]*)\s*:`),
+	}
+}
+
+// mermaidThemes はMermaidのテーマに対応するPlantUMLのテーマです（空文字列はPlantUMLの標準の見た目）
+var mermaidThemes = map[string]string{
+	"default": "",
+	"base":    "plain",
+	"dark":    "cyborg",
+	"forest":  "minty",
+	"neutral": "mono",
+}
+
+// themeVariableSkinparams はMermaidのテーマ変数に対応するPlantUMLの skinparam です（出力順）
+var themeVariableSkinparams = []struct {
+	variable  string
+	skinparam string
+}{
+	{"background", "BackgroundColor"},
+	{"primaryColor", "ClassBackgroundColor"},
+	{"primaryBorderColor", "ClassBorderColor"},
+	{"primaryTextColor", "ClassFontColor"},
+	{"lineColor", "ArrowColor"},
+	{"fontFamily", "DefaultFontName"},
+	{"fontSize", "DefaultFontSize"},
+}
+
+// ApplyDirective はディレクティブの内容（init: {...}）を設定に反映します
+// init 以外のディレクティブやサポートしていない設定項目は無視し、警告メッセージとして返します
+func (p *ConfigParser) ApplyDirective(config *DiagramConfig, text string) ([]string, error) {
+	name, body, _ := strings.Cut(text, ":")
+	name = strings.TrimSpace(name)
+	if name != "init" && name != "initialize" {
+		return []string{fmt.Sprintf("サポートされていないディレクティブです: %s", name)}, nil
+	}
+
+	values, err := p.parseObject(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s ディレクティブを解釈できません: %v", name, err)
+	}
+	return p.ApplyConfig(config, values), nil
+}
+
+// ApplyConfig は設定項目を設定に反映します
+// サポートしていない設定項目は無視し、警告メッセージとして返します
+func (p *ConfigParser) ApplyConfig(config *DiagramConfig, values map[string]interface{}) []string {
+	var warnings []string
+
+	for _, key := range sortedKeys(values) {
+		value := values[key]
+		switch key {
+		case "theme":
+			theme := fmt.Sprint(value)
+			if _, ok := mermaidThemes[theme]; !ok {
+				warnings = append(warnings, fmt.Sprintf("サポートされていないテーマです: %s", theme))
+				continue
+			}
+			config.Theme = theme
+		case "look":
+			look := fmt.Sprint(value)
+			if look != "classic" && look != "handDrawn" {
+				warnings = append(warnings, fmt.Sprintf("サポートされていない描画スタイルです: %s", look))
+				continue
+			}
+			config.Look = look
+		case "fontFamily":
+			config.ThemeVariables[key] = fmt.Sprint(value)
+		case "themeVariables":
+			warnings = append(warnings, p.applyThemeVariables(config, value)...)
+		case "class":
+			warnings = append(warnings, p.applyClassConfig(config, value)...)
+		default:
+			warnings = append(warnings, fmt.Sprintf("サポートされていない設定です: %s", key))
+		}
+	}
+
+	return warnings
+}
+
+// applyThemeVariables はテーマ変数（themeVariables）を設定に反映します
+func (p *ConfigParser) applyThemeVariables(config *DiagramConfig, value interface{}) []string {
+	variables, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{"themeVariables はオブジェクトで指定してください"}
+	}
+
+	var warnings []string
+	for _, name := range sortedKeys(variables) {
+		if p.skinparamFor(name) == "" {
+			warnings = append(warnings, fmt.Sprintf("サポートされていないテーマ変数です: %s", name))
+			continue
+		}
+		config.ThemeVariables[name] = fmt.Sprint(variables[name])
+	}
+	return warnings
+}
+
+// applyClassConfig はクラス図固有の設定（class）を設定に反映します
+func (p *ConfigParser) applyClassConfig(config *DiagramConfig, value interface{}) []string {
+	options, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{"class はオブジェクトで指定してください"}
+	}
+
+	var warnings []string
+	for _, name := range sortedKeys(options) {
+		switch name {
+		case "hideEmptyMembersBox":
+			config.HideEmptyMembers = options[name] == true
+		default:
+			warnings = append(warnings, fmt.Sprintf("サポートされていないクラス図の設定です: %s", name))
+		}
+	}
+	return warnings
+}
+
+// FormatConfig は設定をPlantUMLの !theme・skinparam 指定にフォーマットします
+func (p *ConfigParser) FormatConfig(config *DiagramConfig) string {
+	var result strings.Builder
+
+	if theme := mermaidThemes[config.Theme]; theme != "" {
+		result.WriteString(fmt.Sprintf("!theme %s\n", theme))
+	}
+	if config.Look == "handDrawn" {
+		result.WriteString("skinparam handwritten true\n")
+	}
+	for _, entry := range themeVariableSkinparams {
+		if value, ok := config.ThemeVariables[entry.variable]; ok && value != "" {
+			if entry.variable == "fontSize" {
+				value = strings.TrimSuffix(value, "px")
+			}
+			result.WriteString(fmt.Sprintf("skinparam %s %s\n", entry.skinparam, value))
+		}
+	}
+	if config.HideEmptyMembers {
+		result.WriteString("hide empty members\n")
+	}
+
+	return result.String()
+}
+
+// parseObject はディレクティブ内のオブジェクトを解析します
+// Mermaidで使われるシングルクォートやクォートのないキーもJSONに直してから解析します
+func (p *ConfigParser) parseObject(text string) (map[string]interface{}, error) {
+	text = doubleQuoteStrings(strings.TrimSpace(text))
+	text = p.unquotedKeyPattern.ReplaceAllString(text, `$1"$2":`)
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// doubleQuoteStrings はシングルクォートで囲まれた文字列をダブルクォートの文字列に直します
+// 文字列の中のアポストロフィ（"O'Brien"）はそのまま残し、シングルクォート文字列の中の " はエスケープします
+func doubleQuoteStrings(text string) string {
+	var result strings.Builder
+	var quote rune
+	escaped := false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+			if quote == '\'' && r == '\'' {
+				result.WriteRune(r)
+				continue
+			}
+			result.WriteRune('\\')
+		case quote != 0 && r == '\\':
+			escaped = true
+			continue
+		case quote == 0 && (r == '\'' || r == '"'):
+			quote = r
+			r = '"'
+		case quote != 0 && r == quote:
+			quote = 0
+			r = '"'
+		case quote == '\'' && r == '"':
+			result.WriteRune('\\')
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// skinparamFor はテーマ変数に対応する skinparam 名を返します（対応がない場合は空文字列）
+func (p *ConfigParser) skinparamFor(variable string) string {
+	for _, entry := range themeVariableSkinparams {
+		if entry.variable == variable {
+			return entry.skinparam
+		}
+	}
+	return ""
+}
+
+// sortedKeys は警告の順序を安定させるため、キーを名前順に並べて返します
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigParser_ApplyDirective(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         *DiagramConfig
+		wantWarnings []string
+		wantErr      bool
+	}{
+		{
+			name:  "テーマとテーマ変数",
+			input: `init: {"theme": "dark", "themeVariables": {"primaryColor": "#ff0000", "fontSize": "14px"}}`,
+			want: &DiagramConfig{
+				Theme:          "dark",
+				ThemeVariables: map[string]string{"primaryColor": "#ff0000", "fontSize": "14px"},
+			},
+		},
+		{
+			name:  "シングルクォートとクォートのないキー",
+			input: `initialize: {theme: 'forest', look: 'handDrawn', class: {hideEmptyMembersBox: true}}`,
+			want: &DiagramConfig{
+				Theme:            "forest",
+				Look:             "handDrawn",
+				ThemeVariables:   map[string]string{},
+				HideEmptyMembers: true,
+			},
+		},
+		{
+			name:  "文字列の中のアポストロフィ",
+			input: `init: {"themeVariables": {"fontFamily": "O'Brien Sans", 'primaryColor': 'say "hi"'}}`,
+			want: &DiagramConfig{
+				ThemeVariables: map[string]string{"fontFamily": "O'Brien Sans", "primaryColor": `say "hi"`},
+			},
+		},
+		{
+			name:  "サポートされていない設定",
+			input: `init: {"theme": "sunset", "securityLevel": "loose", "themeVariables": {"noteBkgColor": "#fff"}}`,
+			want:  &DiagramConfig{ThemeVariables: map[string]string{}},
+			wantWarnings: []string{
+				"サポートされていない設定です: securityLevel",
+				"サポートされていないテーマです: sunset",
+				"サポートされていないテーマ変数です: noteBkgColor",
+			},
+		},
+		{
+			name:         "init以外のディレクティブ",
+			input:        "wrap",
+			want:         &DiagramConfig{ThemeVariables: map[string]string{}},
+			wantWarnings: []string{"サポートされていないディレクティブです: wrap"},
+		},
+		{
+			name:    "解釈できないオブジェクト",
+			input:   `init: {"theme": }`,
+			wantErr: true,
+		},
+	}
+
+	p := NewConfigParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &DiagramConfig{ThemeVariables: map[string]string{}}
+			warnings, err := p.ApplyDirective(config, tt.input)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyDirective() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(config, tt.want) {
+				t.Errorf("ApplyDirective() config = %+v, want %+v", config, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("ApplyDirective() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestConfigParser_FormatConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *DiagramConfig
+		want   string
+	}{
+		{
+			name:   "既定の設定",
+			config: &DiagramConfig{Theme: "default", Look: "classic", ThemeVariables: map[string]string{}},
+			want:   "",
+		},
+		{
+			name: "すべての設定",
+			config: &DiagramConfig{
+				Theme:            "neutral",
+				Look:             "handDrawn",
+				ThemeVariables:   map[string]string{"fontSize": "14px", "fontFamily": "Noto Sans", "primaryBorderColor": "#333"},
+				HideEmptyMembers: true,
+			},
+			want: "!theme mono\nskinparam handwritten true\nskinparam ClassBorderColor #333\nskinparam DefaultFontName Noto Sans\nskinparam DefaultFontSize 14\nhide empty members\n",
+		},
+	}
+
+	p := NewConfigParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.FormatConfig(tt.config); got != tt.want {
+				t.Errorf("FormatConfig() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// lineBreakPattern はMermaidの文字列中の改行タグ（<br> / <br/>）に一致します
+var lineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// 行単位で解析する図に共通する文の書式
+var (
+	// directionStatementPattern は本文中の図の向きの指定（direction LR）に一致します
+	directionStatementPattern = regexp.MustCompile(`^direction\s+(\S+)$`)
+	// styleStatementPattern はPlantUMLで表現できないスタイル・アクセシビリティの指定に一致します
+	styleStatementPattern = regexp.MustCompile(`^(classDef|class|style|accTitle|accDescr)[\s:{]`)
+	// cssClassPattern は状態やノードに付けたスタイルクラス（:::name）に一致します
+	cssClassPattern = regexp.MustCompile(`:::[\w-]+`)
+)
+
+// DiagramConverter は1種類のMermaid図をPlantUML形式に変換するコンバーター
+// 新しい種類の図は、このインターフェースを実装して MermaidParser.RegisterConverter で登録します
+type DiagramConverter interface {
+	// Convert は図の種類の宣言を含む入力全体を変換し、PlantUMLと変換時の警告を返します
+	Convert(input string, options ConvertOptions) (string, ParseErrors, error)
+}
+
+// ConvertOptions はコンバーターに渡す変換時のオプションです
+// Direction は図の向きの上書き指定で、指定がない場合は空文字列です
+type ConvertOptions struct {
+	Mode      ParseMode
+	Direction string
+}
+
+// diagnostics は変換モードに応じて、文ごとのエラーをエラーとして返すか警告として記録します
+type diagnostics struct {
+	mode     ParseMode
+	input    string
+	warnings ParseErrors
+}
+
+// newDiagnostics は入力に対するdiagnosticsを作成します
+func newDiagnostics(input string, mode ParseMode) *diagnostics {
+	return &diagnostics{mode: mode, input: input}
+}
+
+// check は文ごとのエラーを確認します
+// lenient モードでは誤りのある文を読み飛ばし、エラーを警告として記録します
+func (d *diagnostics) check(errs ParseErrors) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if d.mode == ModeLenient {
+		d.warnings = append(d.warnings, errs...)
+		return nil
+	}
+	errs.sortByPosition()
+	errs.attachSource(d.input)
+	return errs
+}
+
+// finish はモデル構築時の警告を加えて、位置の順に並べた警告を返します
+// strict モードでは最初の警告をエラーとして返します
+func (d *diagnostics) finish(warnings ParseErrors) (ParseErrors, error) {
+	all := append(d.warnings, warnings...)
+	all.sortByPosition()
+	all.attachSource(d.input)
+	if d.mode == ModeStrict && len(all) > 0 {
+		return nil, all[:1]
+	}
+	return all, nil
+}
+
+// SettingsParser はフロントマターとディレクティブから図全体の設定（タイトル・テーマなど）を構築します
+// どの種類の図でも書式が共通のため、各コンバーターから利用します
+type SettingsParser struct {
+	configParser      *ConfigParser
+	frontmatterParser *FrontmatterParser
+}
+
+// NewSettingsParser は新しいSettingsParserインスタンスを作成します
+func NewSettingsParser() *SettingsParser {
+	return &SettingsParser{
+		configParser:      NewConfigParser(),
+		frontmatterParser: NewFrontmatterParser(),
+	}
+}
+
+// ApplyFrontmatter はフロントマターの設定を反映し、タイトルと警告を返します
+func (p *SettingsParser) ApplyFrontmatter(config *DiagramConfig, node *FrontmatterNode) (string, ParseErrors, error) {
+	values, err := p.frontmatterParser.Parse(node.Text, node.Pos)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var title string
+	var warnings ParseErrors
+	for _, key := range sortedKeys(values) {
+		switch value := values[key]; key {
+		case "title":
+			title = fmt.Sprint(value)
+		case "config":
+			options, ok := value.(map[string]interface{})
+			if !ok {
+				warnings = append(warnings, newParseError(node.Pos, ErrInvalidFrontmatter, "フロントマターの config はマッピングで指定してください"))
+				continue
+			}
+			for _, warning := range p.configParser.ApplyConfig(config, options) {
+				warnings = append(warnings, newParseError(node.Pos, ErrUnsupported, "%s", warning))
+			}
+		default:
+			warnings = append(warnings, newParseError(node.Pos, ErrUnsupported, "サポートされていないフロントマターの項目です: %s", key))
+		}
+	}
+	return title, warnings, nil
+}
+
+// ApplyDirective はディレクティブ（%%{init: {...}}%%）の設定を反映し、警告を返します
+func (p *SettingsParser) ApplyDirective(config *DiagramConfig, node *DirectiveNode) (ParseErrors, error) {
+	messages, err := p.configParser.ApplyDirective(config, node.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings ParseErrors
+	for _, message := range messages {
+		warnings = append(warnings, newParseError(node.Pos, ErrUnsupported, "%s", message))
+	}
+	return warnings, nil
+}
+
+// FormatSettings は設定とタイトルをPlantUMLの指定（!theme・skinparam・title）にフォーマットします
+func (p *SettingsParser) FormatSettings(config *DiagramConfig, title string) string {
+	result := p.configParser.FormatConfig(config)
+	if title != "" {
+		result += fmt.Sprintf("title %s\n", title)
+	}
+	return result
+}
+
+// sourceLine はコメントを取り除いたソースの1行です
+// Pos は行頭の空白を除いた本文の開始位置です
+type sourceLine struct {
+	Pos
+	Text string
+}
+
+// diagramSource は図のソースです
+// 先頭のフロントマターとディレクティブは図全体の設定として分け、残りの行は図の種類の宣言を含めて本文とします
+// body はフロントマターとディレクティブの行を空行に置き換えた入力で、行番号を保ったまま字句解析に使います
+type diagramSource struct {
+	frontmatter *FrontmatterNode
+	directives  []*DirectiveNode
+	lines       []sourceLine
+	body        string
+}
+
+// diagramType は最初の意味のある行の先頭の単語を図の種類として返します
+// 該当する行がない場合は空文字列を返します
+func (s *diagramSource) diagramType() (string, Pos) {
+	if len(s.lines) == 0 {
+		return "", Pos{Line: 1, Column: 1}
+	}
+	return strings.Fields(s.lines[0].Text)[0], s.lines[0].Pos
+}
+
+// splitSource はソースを行に分け、コメントと空行を取り除きます
+// フロントマター・ディレクティブ・図の種類の宣言はすべての図でこの結果から読み取ります
+func splitSource(input string) (*diagramSource, ParseErrors) {
+	source := &diagramSource{}
+	var errs ParseErrors
+
+	lines := strings.Split(input, "\n")
+	body := make([]string, len(lines))
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		start = len(lines)
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				source.frontmatter = &FrontmatterNode{Pos: Pos{Line: 2, Column: 1}, Text: strings.Join(lines[1:i], "\n") + "\n"}
+				start = i + 1
+				break
+			}
+		}
+		if source.frontmatter == nil {
+			errs = append(errs, newParseError(Pos{Line: 1, Column: 1}, ErrInvalidFrontmatter, "フロントマターが --- で閉じられていません"))
+		}
+	}
+
+	var directive *DirectiveNode
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		trimmed := strings.TrimSpace(line)
+		pos := Pos{Line: i + 1, Column: len([]rune(line)) - len([]rune(strings.TrimLeft(line, " \t"))) + 1}
+
+		if directive == nil && strings.HasPrefix(trimmed, "%%{") {
+			directive = &DirectiveNode{Pos: pos}
+			trimmed = strings.TrimPrefix(trimmed, "%%{")
+		}
+		if directive != nil {
+			// ディレクティブは複数行にわたることがある
+			text, _, closed := strings.Cut(trimmed, "}%%")
+			directive.Text = strings.TrimSpace(directive.Text + "\n" + text)
+			if closed {
+				source.directives = append(source.directives, directive)
+				directive = nil
+			}
+			continue
+		}
+
+		body[i] = lines[i]
+		if text := stripComment(trimmed); text != "" {
+			source.lines = append(source.lines, sourceLine{Pos: pos, Text: text})
+		}
+	}
+	if directive != nil {
+		errs = append(errs, newParseError(directive.Pos, ErrInvalidDirective, "ディレクティブが }%%%% で閉じられていません"))
+	}
+
+	source.body = strings.Join(body, "\n")
+	return source, errs
+}
+
+// ApplySource は行単位で解析する図のフロントマターとディレクティブを設定に反映します
+// タイトルと警告に加え、解釈できなかったフロントマター・ディレクティブのエラーを返します
+func (p *SettingsParser) ApplySource(config *DiagramConfig, source *diagramSource) (string, ParseErrors, ParseErrors) {
+	var title string
+	var warnings, errs ParseErrors
+
+	if source.frontmatter != nil {
+		frontmatterTitle, frontmatterWarnings, err := p.ApplyFrontmatter(config, source.frontmatter)
+		if err != nil {
+			errs = append(errs, asParseError(err, source.frontmatter.Pos, ErrInvalidFrontmatter))
+		}
+		title = frontmatterTitle
+		warnings = append(warnings, frontmatterWarnings...)
+	}
+	for _, directive := range source.directives {
+		directiveWarnings, err := p.ApplyDirective(config, directive)
+		if err != nil {
+			errs = append(errs, asParseError(err, directive.Pos, ErrInvalidDirective))
+		}
+		warnings = append(warnings, directiveWarnings...)
+	}
+
+	return title, warnings, errs
+}
+
+// lineConversion は行単位で解析する図の変換中の状態です
+// header は図の種類の宣言の行、lines はそれに続く本文の行です
+type lineConversion struct {
+	settingsParser *SettingsParser
+	diag           *diagnostics
+	config         *DiagramConfig
+	title          string
+	warnings       ParseErrors
+	header         sourceLine
+	lines          []sourceLine
+}
+
+// beginConvert はソースを行に分け、フロントマターとディレクティブの設定を反映して変換を始めます
+func beginConvert(settingsParser *SettingsParser, input string, options ConvertOptions) (*lineConversion, error) {
+	lc := &lineConversion{
+		settingsParser: settingsParser,
+		diag:           newDiagnostics(input, options.Mode),
+		config:         &DiagramConfig{ThemeVariables: make(map[string]string)},
+	}
+
+	source, errs := splitSource(input)
+	if err := lc.diag.check(errs); err != nil {
+		return nil, err
+	}
+
+	lc.title, lc.warnings, errs = settingsParser.ApplySource(lc.config, source)
+	if err := lc.diag.check(errs); err != nil {
+		return nil, err
+	}
+
+	if len(source.lines) > 0 {
+		lc.header, lc.lines = source.lines[0], source.lines[1:]
+	}
+	return lc, nil
+}
+
+// finish は本文の変換で生じたエラーと警告を確認し、図全体の設定を加えたPlantUMLを返します
+func (lc *lineConversion) finish(body string, errs, warnings ParseErrors) (string, ParseErrors, error) {
+	if err := lc.diag.check(errs); err != nil {
+		return "", nil, err
+	}
+	warnings, err := lc.diag.finish(append(lc.warnings, warnings...))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var result strings.Builder
+	result.WriteString("@startuml\n")
+	result.WriteString(lc.settingsParser.FormatSettings(lc.config, lc.title))
+	result.WriteString(body)
+	result.WriteString("@enduml")
+	return result.String(), warnings, nil
+}
+
+// openBlock は行単位で解析する図の、閉じられるまでのブロックです
+type openBlock struct {
+	kind string
+	pos  Pos
+}
+
+// blockWriter はブロックの入れ子に合わせてインデントしながらPlantUMLの行を書き出します
+type blockWriter struct {
+	body   strings.Builder
+	blocks []openBlock
+}
+
+// writeLine は現在のブロックの深さにインデントして1行書き出します
+// outdent が真の場合（else・-- など）はひとつ浅くインデントします
+func (w *blockWriter) writeLine(line string, outdent bool) {
+	depth := len(w.blocks)
+	if outdent && depth > 0 {
+		depth--
+	}
+	w.body.WriteString(strings.Repeat("    ", depth) + line + "\n")
+}
+
+// push はブロックを開きます
+func (w *blockWriter) push(kind string, pos Pos) {
+	w.blocks = append(w.blocks, openBlock{kind: kind, pos: pos})
+}
+
+// pop は最も内側のブロックを閉じて返します
+func (w *blockWriter) pop() openBlock {
+	block := w.blocks[len(w.blocks)-1]
+	w.blocks = w.blocks[:len(w.blocks)-1]
+	return block
+}
+
+// top は最も内側のブロックの種類を返し、ブロックの外では空文字列を返します
+func (w *blockWriter) top() string {
+	if len(w.blocks) == 0 {
+		return ""
+	}
+	return w.blocks[len(w.blocks)-1].kind
+}
@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiagramSource_DiagramType(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantPos Pos
+	}{
+		{
+			name:    "クラス図",
+			input:   "classDiagram\nclass A",
+			want:    "classDiagram",
+			wantPos: Pos{Line: 1, Column: 1},
+		},
+		{
+			name:    "向き付きのフローチャート",
+			input:   "\n  flowchart LR %% 横向き\n  A --> B",
+			want:    "flowchart",
+			wantPos: Pos{Line: 2, Column: 3},
+		},
+		{
+			name:    "コメントと複数行のディレクティブ",
+			input:   "%% 注文の流れ\n%%{init: {\n  \"theme\": \"dark\"\n}}%%\nsequenceDiagram",
+			want:    "sequenceDiagram",
+			wantPos: Pos{Line: 5, Column: 1},
+		},
+		{
+			name:    "フロントマター",
+			input:   "---\ntitle: 状態遷移\n---\nstateDiagram-v2\n[*] --> A",
+			want:    "stateDiagram-v2",
+			wantPos: Pos{Line: 4, Column: 1},
+		},
+		{
+			name:    "空の入力",
+			input:   "\n%% コメントのみ\n",
+			want:    "",
+			wantPos: Pos{Line: 1, Column: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, _ := splitSource(tt.input)
+			got, pos := source.diagramType()
+
+			if got != tt.want || pos != tt.wantPos {
+				t.Errorf("diagramType() got = %q %v, want %q %v", got, pos, tt.want, tt.wantPos)
+			}
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	input := "classDiagram\nA <-.- B\nstyle A opacity:0"
+	errs := ParseErrors{newParseError(Pos{Line: 2, Column: 3}, ErrUnknownArrow, "解釈できない関連の矢印です")}
+	warnings := ParseErrors{newParseError(Pos{Line: 3, Column: 1}, ErrUnsupported, "サポートされていないスタイル属性です: opacity")}
+
+	tests := []struct {
+		name         string
+		mode         ParseMode
+		wantCheckErr bool
+		wantFinish   int
+		wantErr      bool
+	}{
+		{name: "通常モード", mode: ModeDefault, wantCheckErr: true},
+		{name: "lenientモード", mode: ModeLenient, wantFinish: 2},
+		{name: "strictモード", mode: ModeStrict, wantCheckErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := newDiagnostics(input, tt.mode)
+			if err := diag.check(errs); (err != nil) != tt.wantCheckErr {
+				t.Fatalf("check() error = %v, wantErr %v", err, tt.wantCheckErr)
+			}
+			if tt.wantCheckErr {
+				return
+			}
+
+			got, err := diag.finish(warnings)
+			if err != nil {
+				t.Fatalf("finish() error = %v", err)
+			}
+			if len(got) != tt.wantFinish || got[0].Snippet != "A <-.- B" {
+				t.Errorf("finish() got = %v", got)
+			}
+		})
+	}
+
+	// strict モードでは警告もエラーになる
+	if _, err := newDiagnostics(input, ModeStrict).finish(warnings); err == nil {
+		t.Errorf("finish() error = nil, want the first warning")
+	}
+}
+
+func TestSplitSource(t *testing.T) {
+	input := "---\ntitle: 注文\n---\n%%{init: {\n  \"theme\": \"dark\"\n}}%%\nsequenceDiagram\n\n  %% コメント\n  A->>B: x %% 行末のコメント\n"
+
+	source, errs := splitSource(input)
+	if len(errs) != 0 {
+		t.Fatalf("splitSource() errors = %v", errs)
+	}
+	if source.frontmatter == nil || source.frontmatter.Text != "title: 注文\n" || source.frontmatter.Pos != (Pos{Line: 2, Column: 1}) {
+		t.Errorf("splitSource() frontmatter = %+v", source.frontmatter)
+	}
+	if len(source.directives) != 1 || source.directives[0].Text != "init: {\n\"theme\": \"dark\"\n}" || source.directives[0].Pos != (Pos{Line: 4, Column: 1}) {
+		t.Errorf("splitSource() directives = %+v", source.directives)
+	}
+	want := []sourceLine{
+		{Pos: Pos{Line: 7, Column: 1}, Text: "sequenceDiagram"},
+		{Pos: Pos{Line: 10, Column: 3}, Text: "A->>B: x"},
+	}
+	if !reflect.DeepEqual(source.lines, want) {
+		t.Errorf("splitSource() lines = %+v, want %+v", source.lines, want)
+	}
+
+	_, errs = splitSource("---\ntitle: 注文\nsequenceDiagram")
+	if len(errs) != 1 || errs[0].Code != ErrInvalidFrontmatter {
+		t.Errorf("splitSource() errors = %v, want unclosed frontmatter", errs)
+	}
+	_, errs = splitSource("%%{init: {}\nsequenceDiagram")
+	if len(errs) != 1 || errs[0].Code != ErrInvalidDirective {
+		t.Errorf("splitSource() errors = %v, want unclosed directive", errs)
+	}
+}
+
+func TestSettingsParser_ApplySource(t *testing.T) {
+	source, _ := splitSource("---\ntitle: 注文\nconfig:\n  theme: forest\n---\n%%{init: {\"look\": \"handDrawn\"}}%%\n%%{init: {theme: }}%%\nsequenceDiagram")
+	config := &DiagramConfig{ThemeVariables: make(map[string]string)}
+
+	title, warnings, errs := NewSettingsParser().ApplySource(config, source)
+	if title != "注文" || config.Theme != "forest" || config.Look != "handDrawn" || len(warnings) != 0 {
+		t.Errorf("ApplySource() title = %q, config = %+v, warnings = %v", title, config, warnings)
+	}
+	if len(errs) != 1 || errs[0].Code != ErrInvalidDirective || errs[0].Line != 7 {
+		t.Errorf("ApplySource() errors = %v, want the invalid directive on line 7", errs)
+	}
+}
+
+func TestBeginConvert(t *testing.T) {
+	lc, err := beginConvert(NewSettingsParser(), "---\ntitle: 注文\n---\nerDiagram\nA ||--o{ B : has", ConvertOptions{})
+	if err != nil {
+		t.Fatalf("beginConvert() error = %v", err)
+	}
+	if lc.header.Text != "erDiagram" || len(lc.lines) != 1 || lc.lines[0].Text != "A ||--o{ B : has" {
+		t.Errorf("beginConvert() header = %+v, lines = %+v", lc.header, lc.lines)
+	}
+
+	got, warnings, err := lc.finish("A --> B\n", nil, nil)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("finish() warnings = %v, error = %v", warnings, err)
+	}
+	if want := "@startuml\ntitle 注文\nA --> B\n@enduml"; got != want {
+		t.Errorf("finish() got = %q, want %q", got, want)
+	}
+
+	if _, err := beginConvert(NewSettingsParser(), "%%{init: {}\nerDiagram", ConvertOptions{}); err == nil {
+		t.Errorf("beginConvert() error = nil, want unclosed directive")
+	}
+}
+
+// converterErrorTest は変換モードごとのエラーと警告を確認するテストケースです
+// エラーになる場合、want は空文字列です
+type converterErrorTest struct {
+	name         string
+	input        string
+	mode         ParseMode
+	want         string
+	wantErrors   []ParseError
+	wantWarnings []ParseError
+}
+
+// runConverterErrorTests はコンバーターの変換結果・エラー・警告をテストケースと比較します
+func runConverterErrorTests(t *testing.T, converter DiagramConverter, tests []converterErrorTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := converter.Convert(tt.input, ConvertOptions{Mode: tt.mode})
+
+			var errs ParseErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("Convert() error = %v, want ParseErrors", err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+			if gotErrors := derefParseErrors(errs); !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("Convert() errors = %+v, want %+v", gotErrors, tt.wantErrors)
+			}
+			if gotWarnings := derefParseErrors(warnings); !reflect.DeepEqual(gotWarnings, tt.wantWarnings) {
+				t.Errorf("Convert() warnings = %+v, want %+v", gotWarnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+// derefParseErrors はエラーの一覧を比較できる値の一覧にします
+func derefParseErrors(errs ParseErrors) []ParseError {
+	var result []ParseError
+	for _, err := range errs {
+		result = append(result, *err)
+	}
+	return result
+}
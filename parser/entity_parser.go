@@ -0,0 +1,237 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EntityParser はER図のエンティティ・属性・関連の解析を担当します
+type EntityParser struct {
+	entityPattern       *regexp.Regexp
+	attributePattern    *regexp.Regexp
+	relationPattern     *regexp.Regexp
+	wordRelationPattern *regexp.Regexp
+	relationMarker      *regexp.Regexp
+}
+
+// entityNamePattern はエンティティ名（引用符で囲まれた名前を含む）に一致します
+const entityNamePattern = `("[^"]+"|[\p{L}\p{N}_-]+)`
+
+// cardinalityWordPattern は単語によるカーディナリティの指定に一致します
+const cardinalityWordPattern = `(only one|zero or one|one or zero|zero or more|zero or many|one or more|one or many|many\(0\)|many\(1\)|0\+|1\+|1)`
+
+// NewEntityParser は新しいEntityParserインスタンスを作成します
+func NewEntityParser() *EntityParser {
+	return &EntityParser{
+		entityPattern:       regexp.MustCompile(`^` + entityNamePattern + `(?:\s*\[\s*"?([^"\]]*)"?\s*\])?\s*(\{\s*\}|\{)?$`),
+		attributePattern:    regexp.MustCompile(`^([\p{L}_][\p{L}\p{N}_\-\[\]()]*)\s+(\*?[\p{L}_][\p{L}\p{N}_\-\[\]()]*)((?:\s*,?\s*\b(?:PK|FK|UK)\b)*)\s*(?:"([^"]*)")?$`),
+		relationPattern:     regexp.MustCompile(`^` + entityNamePattern + `\s*([|{}o]{2})(--|\.\.)([|{}o]{2})\s*` + entityNamePattern + `\s*(?::\s*(.*))?$`),
+		wordRelationPattern: regexp.MustCompile(`^` + entityNamePattern + `\s+` + cardinalityWordPattern + `\s+(to|optionally to)\s+` + cardinalityWordPattern + `\s+` + entityNamePattern + `\s*(?::\s*(.*))?$`),
+		relationMarker:      regexp.MustCompile(`--|\.\.|\s(?:optionally\s+)?to\s`),
+	}
+}
+
+// sourceCardinalities は関連元の記号に対応するカーディナリティです
+var sourceCardinalities = map[string]string{
+	"|o": "zero-or-one",
+	"||": "exactly-one",
+	"}o": "zero-or-more",
+	"}|": "one-or-more",
+}
+
+// targetCardinalities は関連先の記号に対応するカーディナリティです
+var targetCardinalities = map[string]string{
+	"o|": "zero-or-one",
+	"||": "exactly-one",
+	"o{": "zero-or-more",
+	"|{": "one-or-more",
+}
+
+// cardinalityWords は単語による指定に対応するカーディナリティです
+var cardinalityWords = map[string]string{
+	"only one":     "exactly-one",
+	"1":            "exactly-one",
+	"zero or one":  "zero-or-one",
+	"one or zero":  "zero-or-one",
+	"zero or more": "zero-or-more",
+	"zero or many": "zero-or-more",
+	"many(0)":      "zero-or-more",
+	"0+":           "zero-or-more",
+	"one or more":  "one-or-more",
+	"one or many":  "one-or-more",
+	"many(1)":      "one-or-more",
+	"1+":           "one-or-more",
+}
+
+// ParseEntity はエンティティの宣言（CUSTOMER / CUSTOMER["顧客"] {）を解析します
+// 属性の本体が続く（行末が { の）場合は真を返します
+func (p *EntityParser) ParseEntity(line string) (*EntityDefinition, bool, error) {
+	matches := p.entityPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, false, fmt.Errorf("エンティティの宣言を解釈できません: %s", line)
+	}
+	entity := &EntityDefinition{Name: p.unquote(matches[1]), Label: strings.TrimSpace(matches[2])}
+	return entity, matches[3] == "{", nil
+}
+
+// ParseAttribute はエンティティの属性（型 名前 キー "コメント"）を解析します
+func (p *EntityParser) ParseAttribute(line string) (*EntityAttribute, error) {
+	matches := p.attributePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("エンティティの属性を解釈できません: %s", line)
+	}
+
+	attribute := &EntityAttribute{Type: matches[1], Name: matches[2], Comment: matches[4]}
+	for _, key := range strings.FieldsFunc(matches[3], func(r rune) bool { return r == ',' || r == ' ' }) {
+		attribute.Keys = append(attribute.Keys, key)
+	}
+	return attribute, nil
+}
+
+// IsRelationship は行がエンティティ間の関連かどうかを判定します
+func (p *EntityParser) IsRelationship(line string) bool {
+	return p.relationMarker.MatchString(line)
+}
+
+// ParseRelationship はエンティティ間の関連（CUSTOMER ||--o{ ORDER : places）を解析します
+// カーディナリティは記号と単語（one or more to zero or many など）のどちらでも指定できます
+func (p *EntityParser) ParseRelationship(line string) (*EntityRelationship, error) {
+	var rel *EntityRelationship
+	var label string
+
+	if matches := p.relationPattern.FindStringSubmatch(line); matches != nil {
+		source, ok := sourceCardinalities[matches[2]]
+		if !ok {
+			return nil, fmt.Errorf("解釈できないカーディナリティです: %q", matches[2])
+		}
+		target, ok := targetCardinalities[matches[4]]
+		if !ok {
+			return nil, fmt.Errorf("解釈できないカーディナリティです: %q", matches[4])
+		}
+		rel = &EntityRelationship{
+			Source:            p.unquote(matches[1]),
+			Target:            p.unquote(matches[5]),
+			SourceCardinality: source,
+			TargetCardinality: target,
+			Identifying:       matches[3] == "--",
+		}
+		label = matches[6]
+	} else if matches := p.wordRelationPattern.FindStringSubmatch(line); matches != nil {
+		rel = &EntityRelationship{
+			Source:            p.unquote(matches[1]),
+			Target:            p.unquote(matches[5]),
+			SourceCardinality: cardinalityWords[matches[2]],
+			TargetCardinality: cardinalityWords[matches[4]],
+			Identifying:       matches[3] == "to",
+		}
+		label = matches[6]
+	} else {
+		return nil, fmt.Errorf("エンティティ間の関連を解釈できません: %s", line)
+	}
+
+	if strings.TrimSpace(label) == "" && !strings.Contains(line, ":") {
+		return nil, fmt.Errorf("%s と %s の関連にラベルがありません", rel.Source, rel.Target)
+	}
+	rel.Label = p.unquote(strings.TrimSpace(label))
+	return rel, nil
+}
+
+// FormatEntity はエンティティをPlantUMLの entity 宣言にフォーマットします
+// 主キーの属性は区切り線（--）の上に * 付きで並べ、属性がない場合は本体の括弧を省略します
+func (p *EntityParser) FormatEntity(entity *EntityDefinition) string {
+	header := PlantUMLName(entity.Name)
+	if entity.Label != "" {
+		header = fmt.Sprintf("\"%s\" as %s", entity.Label, header)
+	}
+	if len(entity.Attributes) == 0 {
+		return fmt.Sprintf("entity %s\n", header)
+	}
+
+	var keys, others []string
+	for _, attribute := range entity.Attributes {
+		if p.hasKey(attribute, "PK") {
+			keys = append(keys, p.formatAttribute(attribute))
+		} else {
+			others = append(others, p.formatAttribute(attribute))
+		}
+	}
+
+	var block strings.Builder
+	block.WriteString(fmt.Sprintf("entity %s {\n", header))
+	for _, line := range keys {
+		block.WriteString(fmt.Sprintf("    %s\n", line))
+	}
+	if len(keys) > 0 && len(others) > 0 {
+		block.WriteString("    --\n")
+	}
+	for _, line := range others {
+		block.WriteString(fmt.Sprintf("    %s\n", line))
+	}
+	block.WriteString("}\n")
+	return block.String()
+}
+
+// FormatRelationship は関連をPlantUMLのIE記法の1行にフォーマットします
+func (p *EntityParser) FormatRelationship(rel *EntityRelationship) string {
+	line := "--"
+	if !rel.Identifying {
+		line = ".."
+	}
+	if rel.LayoutDirection != "" {
+		line = line[:1] + rel.LayoutDirection + line[1:]
+	}
+
+	arrow := p.cardinalitySymbol(sourceCardinalities, rel.SourceCardinality) + line + p.cardinalitySymbol(targetCardinalities, rel.TargetCardinality)
+	result := fmt.Sprintf("%s %s %s", PlantUMLName(rel.Source), arrow, PlantUMLName(rel.Target))
+	if rel.Label != "" {
+		result += " : " + rel.Label
+	}
+	return result
+}
+
+// formatAttribute は属性を "名前 : 型 <<キー>>" の形にフォーマットします
+// コメントは行末に // に続けて付けます
+func (p *EntityParser) formatAttribute(attribute *EntityAttribute) string {
+	name := attribute.Name
+	if p.hasKey(attribute, "PK") && !strings.HasPrefix(name, "*") {
+		name = "* " + name
+	}
+
+	line := fmt.Sprintf("%s : %s", name, attribute.Type)
+	if len(attribute.Keys) > 0 {
+		line += fmt.Sprintf(" <<%s>>", strings.Join(attribute.Keys, ", "))
+	}
+	if attribute.Comment != "" {
+		line += " // " + attribute.Comment
+	}
+	return line
+}
+
+// hasKey は属性に指定したキーが含まれるかどうかを判定します
+func (p *EntityParser) hasKey(attribute *EntityAttribute, key string) bool {
+	for _, k := range attribute.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// cardinalitySymbol はカーディナリティに対応するIE記法の記号を返します
+func (p *EntityParser) cardinalitySymbol(symbols map[string]string, cardinality string) string {
+	for symbol, c := range symbols {
+		if c == cardinality {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// unquote はエンティティ名やラベルを囲む引用符を取り除きます
+func (p *EntityParser) unquote(text string) string {
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
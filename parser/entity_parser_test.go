@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntityParser_ParseEntity(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		want     *EntityDefinition
+		wantOpen bool
+		wantErr  bool
+	}{
+		{name: "エンティティ名のみ", line: "CUSTOMER", want: &EntityDefinition{Name: "CUSTOMER"}},
+		{name: "属性の本体", line: "LINE-ITEM {", want: &EntityDefinition{Name: "LINE-ITEM"}, wantOpen: true},
+		{name: "表示名", line: `CUSTOMER["顧客"] {`, want: &EntityDefinition{Name: "CUSTOMER", Label: "顧客"}, wantOpen: true},
+		{name: "空の本体", line: `"Sales Rep" { }`, want: &EntityDefinition{Name: "Sales Rep"}},
+		{name: "解釈できない宣言", line: "CUSTOMER ORDER", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, open, err := NewEntityParser().ParseEntity(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEntity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) || open != tt.wantOpen {
+				t.Errorf("ParseEntity() got = %+v %v, want %+v %v", got, open, tt.want, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestEntityParser_ParseAttribute(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *EntityAttribute
+		wantErr bool
+	}{
+		{name: "型と名前", line: "string name", want: &EntityAttribute{Type: "string", Name: "name"}},
+		{name: "主キーとコメント", line: `string custNumber PK "顧客番号"`, want: &EntityAttribute{Type: "string", Name: "custNumber", Keys: []string{"PK"}, Comment: "顧客番号"}},
+		{name: "複数のキー", line: "int sector FK, UK", want: &EntityAttribute{Type: "int", Name: "sector", Keys: []string{"FK", "UK"}}},
+		{name: "引数付きの型", line: "varchar(255) email UK", want: &EntityAttribute{Type: "varchar(255)", Name: "email", Keys: []string{"UK"}}},
+		{name: "名前のない属性", line: "string", wantErr: true},
+		{name: "未知のキー", line: "string name IDX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEntityParser().ParseAttribute(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttribute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAttribute() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntityParser_ParseRelationship(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *EntityRelationship
+		wantErr bool
+	}{
+		{
+			name: "識別関連",
+			line: "CUSTOMER ||--o{ ORDER : places",
+			want: &EntityRelationship{Source: "CUSTOMER", Target: "ORDER", SourceCardinality: "exactly-one", TargetCardinality: "zero-or-more", Identifying: true, Label: "places"},
+		},
+		{
+			name: "非識別関連",
+			line: `PRODUCT |o..|{ LINE-ITEM : "is listed in"`,
+			want: &EntityRelationship{Source: "PRODUCT", Target: "LINE-ITEM", SourceCardinality: "zero-or-one", TargetCardinality: "one-or-more", Label: "is listed in"},
+		},
+		{
+			name: "空のラベル",
+			line: `CUSTOMER }o--o| ADDRESS : ""`,
+			want: &EntityRelationship{Source: "CUSTOMER", Target: "ADDRESS", SourceCardinality: "zero-or-more", TargetCardinality: "zero-or-one", Identifying: true},
+		},
+		{
+			name: "単語によるカーディナリティ",
+			line: `CUSTOMER one or more optionally to zero or one "Sales Rep" : has`,
+			want: &EntityRelationship{Source: "CUSTOMER", Target: "Sales Rep", SourceCardinality: "one-or-more", TargetCardinality: "zero-or-one", Label: "has"},
+		},
+		{
+			name: "数字によるカーディナリティ",
+			line: "ORDER 1 to 0+ LINE : contains",
+			want: &EntityRelationship{Source: "ORDER", Target: "LINE", SourceCardinality: "exactly-one", TargetCardinality: "zero-or-more", Identifying: true, Label: "contains"},
+		},
+		{name: "解釈できないカーディナリティ", line: "A |{--o{ B : x", wantErr: true},
+		{name: "ラベルのない関連", line: "A ||--o{ B", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEntityParser().ParseRelationship(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRelationship() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRelationship() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntityParser_FormatEntity(t *testing.T) {
+	entity := &EntityDefinition{
+		Name:  "CUSTOMER",
+		Label: "顧客",
+		Attributes: []*EntityAttribute{
+			{Type: "string", Name: "name", Comment: "氏名"},
+			{Type: "string", Name: "id", Keys: []string{"PK"}},
+			{Type: "int", Name: "sector", Keys: []string{"FK", "UK"}},
+		},
+	}
+	want := "entity \"顧客\" as CUSTOMER {\n    * id : string <<PK>>\n    --\n    name : string // 氏名\n    sector : int <<FK, UK>>\n}\n"
+
+	p := NewEntityParser()
+	if got := p.FormatEntity(entity); got != want {
+		t.Errorf("FormatEntity() got = %v, want %v", got, want)
+	}
+	if got := p.FormatEntity(&EntityDefinition{Name: "LINE-ITEM"}); got != "entity \"LINE-ITEM\"\n" {
+		t.Errorf("FormatEntity() got = %v", got)
+	}
+}
+
+func TestEntityParser_FormatRelationship(t *testing.T) {
+	tests := []struct {
+		name string
+		rel  *EntityRelationship
+		want string
+	}{
+		{
+			name: "識別関連",
+			rel:  &EntityRelationship{Source: "A", Target: "B", SourceCardinality: "exactly-one", TargetCardinality: "zero-or-more", Identifying: true, Label: "has"},
+			want: "A ||--o{ B : has",
+		},
+		{
+			name: "非識別関連",
+			rel:  &EntityRelationship{Source: "A", Target: "B", SourceCardinality: "one-or-more", TargetCardinality: "zero-or-one"},
+			want: "A }|..o| B",
+		},
+		{
+			name: "方向ヒント",
+			rel:  &EntityRelationship{Source: "A", Target: "B", SourceCardinality: "zero-or-one", TargetCardinality: "one-or-more", LayoutDirection: "up"},
+			want: "A |o.up.|{ B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewEntityParser().FormatRelationship(tt.rel); got != tt.want {
+				t.Errorf("FormatRelationship() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
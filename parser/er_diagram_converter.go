@@ -0,0 +1,136 @@
+package parser
+
+import "strings"
+
+// ERDiagramConverter はMermaidのER図をPlantUMLのIE記法のER図に変換するコンバーター
+type ERDiagramConverter struct {
+	entityParser   *EntityParser
+	settingsParser *SettingsParser
+}
+
+// NewERDiagramConverter は新しいERDiagramConverterインスタンスを作成します
+func NewERDiagramConverter() *ERDiagramConverter {
+	return &ERDiagramConverter{
+		entityParser:   NewEntityParser(),
+		settingsParser: NewSettingsParser(),
+	}
+}
+
+// erDiagram はER図のモデルです
+// エンティティは宣言または関連で最初に現れた順に並べます
+type erDiagram struct {
+	Direction     string
+	Entities      []*EntityDefinition
+	Relationships []*EntityRelationship
+	Warnings      ParseErrors
+	entityIndex   map[string]*EntityDefinition
+}
+
+// ensureEntity はエンティティを取得し、存在しない場合は作成します
+func (d *erDiagram) ensureEntity(name string) *EntityDefinition {
+	if entity, ok := d.entityIndex[name]; ok {
+		return entity
+	}
+	entity := &EntityDefinition{Name: name}
+	d.entityIndex[name] = entity
+	d.Entities = append(d.Entities, entity)
+	return entity
+}
+
+// Convert はER図をPlantUML形式に変換し、変換時の警告とともに返します
+func (c *ERDiagramConverter) Convert(input string, options ConvertOptions) (string, ParseErrors, error) {
+	lc, err := beginConvert(c.settingsParser, input, options)
+	if err != nil {
+		return "", nil, err
+	}
+	model, errs := c.buildERDiagram(lc.lines, options.Direction)
+
+	var body strings.Builder
+	body.WriteString("hide circle\n")
+	if layout := directionLayouts[model.Direction]; layout.directive != "" {
+		body.WriteString(layout.directive + "\n")
+	}
+	for _, entity := range model.Entities {
+		body.WriteString(c.entityParser.FormatEntity(entity))
+	}
+	for _, rel := range model.Relationships {
+		body.WriteString(c.entityParser.FormatRelationship(rel) + "\n")
+	}
+	return lc.finish(body.String(), errs, model.Warnings)
+}
+
+// buildERDiagram は図の本文の行からER図のモデルを構築します
+// direction は図の向きの上書き指定で、誤りのある行を除いたモデルと行ごとのエラーを返します
+func (c *ERDiagramConverter) buildERDiagram(lines []sourceLine, direction string) (*erDiagram, ParseErrors) {
+	model := &erDiagram{entityIndex: make(map[string]*EntityDefinition)}
+	var errs ParseErrors
+
+	var current *EntityDefinition
+	var currentPos Pos
+	for _, line := range lines {
+		text := line.Text
+
+		if current != nil {
+			if text == "}" {
+				current = nil
+				continue
+			}
+			attribute, err := c.entityParser.ParseAttribute(text)
+			if err != nil {
+				errs = append(errs, asParseError(err, line.Pos, ErrInvalidEntity))
+				continue
+			}
+			current.Attributes = append(current.Attributes, attribute)
+			continue
+		}
+
+		switch {
+		case directionStatementPattern.MatchString(text):
+			value := strings.ToUpper(directionStatementPattern.FindStringSubmatch(text)[1])
+			if _, ok := directionLayouts[value]; !ok {
+				errs = append(errs, newParseError(line.Pos, ErrInvalidDirection, "サポートされていない図の向き: %s", value))
+				continue
+			}
+			model.Direction = value
+
+		case styleStatementPattern.MatchString(text):
+			keyword := styleStatementPattern.FindStringSubmatch(text)[1]
+			model.Warnings = append(model.Warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLのER図では %s を表現できないため無視します", keyword))
+
+		case c.entityParser.IsRelationship(text):
+			rel, err := c.entityParser.ParseRelationship(text)
+			if err != nil {
+				errs = append(errs, asParseError(err, line.Pos, ErrInvalidRelation))
+				continue
+			}
+			model.ensureEntity(rel.Source)
+			model.ensureEntity(rel.Target)
+			model.Relationships = append(model.Relationships, rel)
+
+		default:
+			entity, open, err := c.entityParser.ParseEntity(text)
+			if err != nil {
+				errs = append(errs, asParseError(err, line.Pos, ErrInvalidEntity))
+				continue
+			}
+			declared := model.ensureEntity(entity.Name)
+			if entity.Label != "" {
+				declared.Label = entity.Label
+			}
+			if open {
+				current, currentPos = declared, line.Pos
+			}
+		}
+	}
+	if current != nil {
+		errs = append(errs, newParseError(currentPos, ErrInvalidEntity, "%s の属性が } で閉じられていません", current.Name))
+	}
+
+	if direction != "" {
+		model.Direction = direction
+	}
+	for _, rel := range model.Relationships {
+		rel.LayoutDirection = directionLayouts[model.Direction].hint
+	}
+	return model, errs
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestERDiagramConverter_Convert(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		direction string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:  "関連から参照されたエンティティ",
+			input: "erDiagram\nCUSTOMER ||--o{ ORDER : places\nORDER ||--|{ LINE-ITEM : contains",
+			want:  "@startuml\nhide circle\nentity CUSTOMER\nentity ORDER\nentity \"LINE-ITEM\"\nCUSTOMER ||--o{ ORDER : places\nORDER ||--|{ \"LINE-ITEM\" : contains\n@enduml",
+		},
+		{
+			name: "属性",
+			input: `erDiagram
+    CUSTOMER["顧客"] {
+        string name
+        string custNumber PK "顧客番号"
+    }
+    CUSTOMER }|..|{ ADDRESS : uses`,
+			want: `@startuml
+hide circle
+entity "顧客" as CUSTOMER {
+    * custNumber : string <<PK>> // 顧客番号
+    --
+    name : string
+}
+entity ADDRESS
+CUSTOMER }|..|{ ADDRESS : uses
+@enduml`,
+		},
+		{
+			name:  "図の向き",
+			input: "---\ntitle: 注文\n---\nerDiagram\ndirection LR\nA ||--o| B : has",
+			want:  "@startuml\ntitle 注文\nhide circle\nleft to right direction\nentity A\nentity B\nA ||--o| B : has\n@enduml",
+		},
+		{
+			name:      "向きの上書き",
+			input:     "erDiagram\nA ||--o| B : has",
+			direction: "RL",
+			want:      "@startuml\nhide circle\nentity A\nentity B\nA ||-left-o| B : has\n@enduml",
+		},
+		{
+			name:    "閉じられていない属性",
+			input:   "erDiagram\nCUSTOMER {\nstring name",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := NewERDiagramConverter().Convert(tt.input, ConvertOptions{Direction: tt.direction})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestERDiagramConverter_Errors(t *testing.T) {
+	input := "erDiagram\nA |{--o{ B : x\nCUSTOMER {\n  string\n}\ndirection XY\nclassDef hot fill:#f00"
+	errs := []ParseError{
+		{Line: 2, Column: 1, Snippet: "A |{--o{ B : x", Code: ErrInvalidRelation, Message: `解釈できないカーディナリティです: "|{"`},
+		{Line: 4, Column: 3, Snippet: "  string", Code: ErrInvalidEntity, Message: "エンティティの属性を解釈できません: string"},
+		{Line: 6, Column: 1, Snippet: "direction XY", Code: ErrInvalidDirection, Message: "サポートされていない図の向き: XY"},
+	}
+	unsupported := ParseError{Line: 7, Column: 1, Snippet: "classDef hot fill:#f00", Code: ErrUnsupported, Message: "PlantUMLのER図では classDef を表現できないため無視します"}
+
+	runConverterErrorTests(t, NewERDiagramConverter(), []converterErrorTest{
+		{name: "通常モード", input: input, wantErrors: errs},
+		{
+			name:         "lenient モード",
+			input:        input,
+			mode:         ModeLenient,
+			want:         "@startuml\nhide circle\nentity CUSTOMER\n@enduml",
+			wantWarnings: append(errs, unsupported),
+		},
+	})
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrorCode はエラーの種類を表現します
+type ErrorCode string
+
+const (
+	ErrUnexpectedToken    ErrorCode = "unexpected-token"    // 文法上予期しないトークン
+	ErrUnknownArrow       ErrorCode = "unknown-arrow"       // 解釈できない関連の矢印
+	ErrMissingValue       ErrorCode = "missing-value"       // 必要な値（メンバー・スタイルクラス名など）の記述漏れ
+	ErrInvalidClass       ErrorCode = "invalid-class"       // クラス名・メンバー・ジェネリック型の誤り
+	ErrInvalidRelation    ErrorCode = "invalid-relation"    // 関連・状態遷移の誤り
+	ErrInvalidState       ErrorCode = "invalid-state"       // 状態の宣言の誤り
+	ErrInvalidEntity      ErrorCode = "invalid-entity"      // エンティティ名・属性の誤り
+	ErrInvalidDirection   ErrorCode = "invalid-direction"   // サポートされていない図の向き
+	ErrInvalidDirective   ErrorCode = "invalid-directive"   // 解釈できないディレクティブ
+	ErrInvalidFrontmatter ErrorCode = "invalid-frontmatter" // 解釈できないフロントマター
+	ErrUnsupportedDiagram ErrorCode = "unsupported-diagram" // 変換できない図の種類
+
+	ErrUnsupported         ErrorCode = "unsupported"           // PlantUMLで表現できない指定（通常は警告）
+	ErrUndefinedStyleClass ErrorCode = "undefined-style-class" // 定義されていないスタイルクラスの参照（通常は警告）
+)
+
+// ParseError は位置情報付きの変換エラーを表現します
+// Snippet はエラーが発生した行のソースで、File は呼び出し側が設定する入力ファイル名です
+type ParseError struct {
+	File    string
+	Line    int
+	Column  int
+	Snippet string
+	Code    ErrorCode
+	Message string
+}
+
+// newParseError は指定した位置のParseErrorを作成します
+func newParseError(pos Pos, code ErrorCode, format string, args ...interface{}) *ParseError {
+	return &ParseError{
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error はエラーメッセージを返します
+// ファイル名が設定されている場合は、エディタが解釈できる file:line:column 形式にします
+func (e *ParseError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d行%d列: %s", e.Line, e.Column, e.Message)
+}
+
+// ParseErrors は1回の変換で見つかった複数のエラー（または警告）を表現します
+type ParseErrors []*ParseError
+
+// Error はすべてのエラーメッセージを改行で連結して返します
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// SetFile はすべてのエラーに入力ファイル名を設定します
+func (e ParseErrors) SetFile(file string) {
+	for _, err := range e {
+		err.File = file
+	}
+}
+
+// sortByPosition はエラーをソース上の位置の順に並べ替えます
+func (e ParseErrors) sortByPosition() {
+	sort.SliceStable(e, func(i, j int) bool {
+		if e[i].Line != e[j].Line {
+			return e[i].Line < e[j].Line
+		}
+		return e[i].Column < e[j].Column
+	})
+}
+
+// attachSource はエラーが発生した行のソースをSnippetに設定します
+func (e ParseErrors) attachSource(input string) {
+	lines := strings.Split(input, "\n")
+	for _, err := range e {
+		if err.Line >= 1 && err.Line <= len(lines) {
+			err.Snippet = strings.TrimRight(lines[err.Line-1], "\r")
+		}
+	}
+}
+
+// asParseError はエラーをParseErrorに変換します
+// ParseErrorでないエラーは、指定した位置と種類のParseErrorとして包みます
+func asParseError(err error, pos Pos, code ErrorCode) *ParseError {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr
+	}
+	return newParseError(pos, code, "%v", err)
+}
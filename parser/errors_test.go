@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ParseError
+		want string
+	}{
+		{
+			name: "ファイル名あり",
+			err:  &ParseError{File: "order.mmd", Line: 12, Column: 5, Code: ErrUnknownArrow, Message: `解釈できない関連の矢印です: "<-.-"`},
+			want: `order.mmd:12:5: 解釈できない関連の矢印です: "<-.-"`,
+		},
+		{
+			name: "ファイル名なし",
+			err:  &ParseError{Line: 3, Column: 1, Code: ErrUnexpectedToken, Message: "予期しないEOFです"},
+			want: "3行1列: 予期しないEOFです",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	errs := ParseErrors{
+		newParseError(Pos{Line: 2, Column: 3}, ErrUnknownArrow, "解釈できない関連の矢印です: %q", "<-.-"),
+		newParseError(Pos{Line: 9, Column: 1}, ErrUnexpectedToken, "予期しないEOFです"),
+	}
+	errs.attachSource("classDiagram\r\nA <-.- B\r\n")
+	errs.SetFile("order.mmd")
+
+	if errs[0].Snippet != "A <-.- B" || errs[1].Snippet != "" {
+		t.Errorf("attachSource() snippets = %q, %q", errs[0].Snippet, errs[1].Snippet)
+	}
+
+	want := "order.mmd:2:3: 解釈できない関連の矢印です: \"<-.-\"\norder.mmd:9:1: 予期しないEOFです"
+	if got := errs.Error(); got != want {
+		t.Errorf("Error() got = %q, want %q", got, want)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(error(errs[0]), &parseErr) || asParseError(errs[0], Pos{}, ErrInvalidClass) != errs[0] {
+		t.Errorf("asParseError() should return the ParseError as is")
+	}
+	if got := asParseError(errors.New("不正"), Pos{Line: 4, Column: 2}, ErrInvalidClass); got.Line != 4 || got.Column != 2 || got.Code != ErrInvalidClass {
+		t.Errorf("asParseError() got = %+v", got)
+	}
+}
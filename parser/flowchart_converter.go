@@ -0,0 +1,534 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// FlowchartConverter はMermaidのフローチャートをPlantUMLに変換するコンバーター
+// 開始ノードが1つの閉路のないグラフは構造化されたアクティビティ図に、それ以外は
+// 要素と矢印だけで表現する汎用のグラフ（rectangle・usecase など）に変換します
+type FlowchartConverter struct {
+	flowchartParser *FlowchartParser
+	settingsParser  *SettingsParser
+}
+
+// NewFlowchartConverter は新しいFlowchartConverterインスタンスを作成します
+func NewFlowchartConverter() *FlowchartConverter {
+	return &FlowchartConverter{
+		flowchartParser: NewFlowchartParser(),
+		settingsParser:  NewSettingsParser(),
+	}
+}
+
+// flowStylePattern はフローチャートに固有の、PlantUMLで表現できない指定に一致します
+var flowStylePattern = regexp.MustCompile(`^(linkStyle|click)[\s:{]`)
+
+// flowchart はフローチャートのモデルです
+// ノードとサブグラフは最初に現れた順に並べます
+type flowchart struct {
+	Direction     string
+	Nodes         []*FlowNode
+	Edges         []*FlowEdge
+	Subgraphs     []*FlowSubgraph
+	Warnings      ParseErrors
+	nodeIndex     map[string]*FlowNode
+	subgraphIndex map[string]*FlowSubgraph
+}
+
+// addNode はノードを追加し、登録済みの場合は形とラベルを更新します
+// サブグラフの中で最初に現れたノードは、そのサブグラフに所属させます
+func (f *flowchart) addNode(node *FlowNode, subgraph string) {
+	existing, ok := f.nodeIndex[node.ID]
+	if !ok {
+		existing = &FlowNode{ID: node.ID}
+		f.nodeIndex[node.ID] = existing
+		f.Nodes = append(f.Nodes, existing)
+	}
+	if node.Shape != "" {
+		existing.Shape = node.Shape
+		existing.Label = node.Label
+	}
+	if existing.Subgraph == "" {
+		existing.Subgraph = subgraph
+	}
+}
+
+// Convert はフローチャートをPlantUML形式に変換し、変換時の警告とともに返します
+func (c *FlowchartConverter) Convert(input string, options ConvertOptions) (string, ParseErrors, error) {
+	lc, err := beginConvert(c.settingsParser, input, options)
+	if err != nil {
+		return "", nil, err
+	}
+	model, errs := c.buildFlowchart(lc.header, lc.lines, options.Direction)
+
+	body, ok := c.formatActivity(model)
+	if !ok {
+		body = c.formatGraph(model)
+	}
+	return lc.finish(body, errs, model.Warnings)
+}
+
+// buildFlowchart は図の種類の宣言と本文の行からフローチャートのモデルを構築します
+// direction は図の向きの上書き指定で、誤りのある文を除いたモデルと文ごとのエラーを返します
+func (c *FlowchartConverter) buildFlowchart(header sourceLine, lines []sourceLine, direction string) (*flowchart, ParseErrors) {
+	model := &flowchart{
+		Direction:     "TB",
+		nodeIndex:     make(map[string]*FlowNode),
+		subgraphIndex: make(map[string]*FlowSubgraph),
+	}
+	var errs ParseErrors
+
+	if header.Text != "" {
+		// 宣言の後ろにセミコロンで区切って文を続けることができる（graph TD;A-->B;）
+		if text, rest, ok := strings.Cut(header.Text, ";"); ok {
+			header.Text = strings.TrimSpace(text)
+			lines = append([]sourceLine{{Pos: header.Pos, Text: rest}}, lines...)
+		}
+
+		headerDirection, err := c.flowchartParser.ParseHeader(header.Text)
+		if err != nil {
+			errs = append(errs, asParseError(err, header.Pos, ErrInvalidDirection))
+		} else {
+			model.Direction = headerDirection
+		}
+	}
+
+	// 開いているサブグラフ（kind はサブグラフのID）
+	var subgraphs []openBlock
+	current := func() string {
+		if len(subgraphs) == 0 {
+			return ""
+		}
+		return subgraphs[len(subgraphs)-1].kind
+	}
+
+	for _, line := range lines {
+		text := line.Text
+
+		switch {
+		case text == "end":
+			if len(subgraphs) == 0 {
+				errs = append(errs, newParseError(line.Pos, ErrUnexpectedToken, "対応するサブグラフのない end です"))
+				continue
+			}
+			subgraphs = subgraphs[:len(subgraphs)-1]
+
+		case text == "subgraph" || strings.HasPrefix(text, "subgraph "):
+			subgraph, err := c.flowchartParser.ParseSubgraph(text)
+			if err != nil {
+				errs = append(errs, asParseError(err, line.Pos, ErrMissingValue))
+				continue
+			}
+			subgraph.Parent = current()
+			if _, ok := model.subgraphIndex[subgraph.ID]; !ok {
+				model.subgraphIndex[subgraph.ID] = subgraph
+				model.Subgraphs = append(model.Subgraphs, subgraph)
+			}
+			subgraphs = append(subgraphs, openBlock{kind: subgraph.ID, pos: line.Pos})
+
+		case directionStatementPattern.MatchString(text):
+			model.Warnings = append(model.Warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLではサブグラフごとの向きを指定できないため無視します"))
+
+		case styleStatementPattern.MatchString(text), flowStylePattern.MatchString(text):
+			keyword := text[:strings.IndexAny(text, " \t:{")]
+			model.Warnings = append(model.Warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLでは %s を表現できないため無視します", keyword))
+
+		default:
+			if cssClassPattern.MatchString(text) {
+				model.Warnings = append(model.Warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLではスタイルクラスを表現できないため無視します"))
+				text = cssClassPattern.ReplaceAllString(text, "")
+			}
+			for _, statement := range strings.Split(text, ";") {
+				if strings.TrimSpace(statement) == "" {
+					continue
+				}
+				nodes, edges, err := c.flowchartParser.ParseStatement(strings.TrimSpace(statement))
+				if err != nil {
+					errs = append(errs, asParseError(err, line.Pos, ErrInvalidRelation))
+					break
+				}
+				for _, node := range nodes {
+					model.addNode(node, current())
+				}
+				model.Edges = append(model.Edges, edges...)
+			}
+		}
+	}
+	for _, subgraph := range subgraphs {
+		errs = append(errs, newParseError(subgraph.pos, ErrUnexpectedToken, "サブグラフ %s が end で閉じられていません", subgraph.kind))
+	}
+
+	// サブグラフを矢印でつないだ場合、サブグラフのIDはノードとして扱わない
+	nodes := model.Nodes[:0]
+	for _, node := range model.Nodes {
+		if _, ok := model.subgraphIndex[node.ID]; ok && node.Shape == "" {
+			delete(model.nodeIndex, node.ID)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	model.Nodes = nodes
+
+	if direction != "" {
+		model.Direction = direction
+	}
+	for _, edge := range model.Edges {
+		edge.LayoutDirection = directionLayouts[model.Direction].hint
+	}
+	return model, errs
+}
+
+// activityEndings はノードの形に対応するアクティビティの終端文字です
+var activityEndings = map[string]string{
+	"subroutine": "|",
+	"asymmetric": ">",
+	"cylinder":   "]",
+	"hexagon":    "}",
+}
+
+// activityEmitter はフローチャートを構造化されたアクティビティ図として書き出します
+type activityEmitter struct {
+	model     *flowchart
+	outgoing  map[string][]*FlowEdge
+	reachable map[string]map[string]bool
+	order     map[string]int
+	emitted   map[string]bool
+	writer    blockWriter
+}
+
+// formatActivity はフローチャートをアクティビティ図にフォーマットします
+// アクティビティ図は上から下にしか配置できないため、横向きや下から上の図は偽を返します
+// サブグラフを含む場合や、開始ノードが1つの閉路のないグラフでない場合、
+// 分岐と合流を if / switch / split の入れ子で表現できない場合も偽を返します
+func (c *FlowchartConverter) formatActivity(model *flowchart) (string, bool) {
+	if model.Direction != "TB" && model.Direction != "TD" {
+		return "", false
+	}
+	if len(model.Nodes) == 0 || len(model.Subgraphs) > 0 {
+		return "", false
+	}
+
+	e := &activityEmitter{
+		model:     model,
+		outgoing:  make(map[string][]*FlowEdge),
+		reachable: make(map[string]map[string]bool),
+		order:     make(map[string]int),
+		emitted:   make(map[string]bool),
+	}
+	incoming := make(map[string]int)
+	for _, edge := range model.Edges {
+		if edge.Head != "arrow" || edge.Tail != "none" || edge.Line == "invisible" {
+			return "", false
+		}
+		e.outgoing[edge.From] = append(e.outgoing[edge.From], edge)
+		incoming[edge.To]++
+	}
+
+	var starts []string
+	for _, node := range model.Nodes {
+		if incoming[node.ID] == 0 {
+			starts = append(starts, node.ID)
+		}
+	}
+	if len(starts) != 1 || !e.sortTopologically(starts[0], incoming) {
+		return "", false
+	}
+
+	e.writer.writeLine("start", false)
+	if end, ok := e.emit(starts[0], map[string]bool{}); !ok || end != "" {
+		return "", false
+	}
+	if len(e.emitted) != len(model.Nodes) {
+		return "", false
+	}
+	return e.writer.body.String(), true
+}
+
+// sortTopologically は開始ノードから順にノードの位置を決めます
+// 閉路がある場合や、開始ノードから到達できないノードがある場合は偽を返します
+func (e *activityEmitter) sortTopologically(start string, incoming map[string]int) bool {
+	remaining := make(map[string]int, len(incoming))
+	for id, count := range incoming {
+		remaining[id] = count
+	}
+
+	queue := []string{start}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		e.order[id] = len(e.order)
+		for _, edge := range e.outgoing[id] {
+			remaining[edge.To]--
+			if remaining[edge.To] == 0 {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return len(e.order) == len(e.model.Nodes)
+}
+
+// reach は指定したノードから到達できるノード（自身を含む）を返します
+func (e *activityEmitter) reach(id string) map[string]bool {
+	if nodes, ok := e.reachable[id]; ok {
+		return nodes
+	}
+	nodes := map[string]bool{id: true}
+	for _, edge := range e.outgoing[id] {
+		for next := range e.reach(edge.To) {
+			nodes[next] = true
+		}
+	}
+	e.reachable[id] = nodes
+	return nodes
+}
+
+// merge は分岐したすべての経路が合流する最初のノードを返し、合流しない場合は空文字列を返します
+func (e *activityEmitter) merge(edges []*FlowEdge) string {
+	merge := ""
+	for id := range e.reach(edges[0].To) {
+		common := true
+		for _, edge := range edges[1:] {
+			common = common && e.reach(edge.To)[id]
+		}
+		if common && (merge == "" || e.order[id] < e.order[merge]) {
+			merge = id
+		}
+	}
+	return merge
+}
+
+// emit はノードから停止ノード（stops）に達するまでの経路を書き出し、達した停止ノードを返します
+// 終端ノードで終わった場合は空文字列を返し、同じノードを2度書き出す場合は偽を返します
+func (e *activityEmitter) emit(id string, stops map[string]bool) (string, bool) {
+	for !stops[id] {
+		if e.emitted[id] {
+			return "", false
+		}
+		e.emitted[id] = true
+		node := e.model.nodeIndex[id]
+		edges := e.outgoing[id]
+
+		switch {
+		case len(edges) == 0:
+			e.writer.writeLine(e.action(node), false)
+			e.writer.writeLine("stop", false)
+			return "", true
+		case len(edges) == 1:
+			e.writer.writeLine(e.action(node), false)
+			if arrow := e.arrow(edges[0]); arrow != "" {
+				e.writer.writeLine(arrow, false)
+			}
+			id = edges[0].To
+		default:
+			merge := e.merge(edges)
+			if !e.branch(node, edges, merge, stops) {
+				return "", false
+			}
+			if merge == "" {
+				return "", true
+			}
+			id = merge
+		}
+	}
+	return id, true
+}
+
+// branch は分岐を書き出します
+// ひし形のノードは条件分岐（2方向は if、3方向以上は switch）、それ以外は split で表現します
+// 各経路は合流ノードか終端ノードで終わる必要があり、そうでない場合は偽を返します
+func (e *activityEmitter) branch(node *FlowNode, edges []*FlowEdge, merge string, stops map[string]bool) bool {
+	branchStops := map[string]bool{merge: merge != ""}
+	for id := range stops {
+		branchStops[id] = true
+	}
+
+	label := node.Label
+	if label == "" {
+		label = node.ID
+	}
+
+	var open, closing string
+	switch {
+	case node.Shape == "diamond" && len(edges) == 2:
+		open, closing = fmt.Sprintf("if (%s) then%s", label, e.condition(edges[0])), "endif"
+	case node.Shape == "diamond":
+		open, closing = fmt.Sprintf("switch (%s)", label), "endswitch"
+	default:
+		e.writer.writeLine(e.action(node), false)
+		open, closing = "split", "end split"
+	}
+	e.writer.writeLine(open, false)
+	e.writer.push(open, Pos{})
+
+	for i, edge := range edges {
+		switch {
+		case open == "split" && i > 0:
+			e.writer.writeLine("split again", true)
+		case closing == "endif" && i > 0:
+			e.writer.writeLine("else"+e.condition(edge), true)
+		case closing == "endswitch":
+			e.writer.writeLine("case"+e.condition(edge), true)
+		}
+		if open == "split" {
+			if arrow := e.arrow(edge); arrow != "" {
+				e.writer.writeLine(arrow, false)
+			}
+		}
+
+		end, ok := e.emit(edge.To, branchStops)
+		if !ok || (end != "" && end != merge) {
+			return false
+		}
+	}
+
+	e.writer.pop()
+	e.writer.writeLine(closing, false)
+	return true
+}
+
+// action はノードをアクティビティ（:ラベル;）にフォーマットします
+func (e *activityEmitter) action(node *FlowNode) string {
+	label := node.Label
+	if label == "" {
+		label = node.ID
+	}
+	ending, ok := activityEndings[node.Shape]
+	if !ok {
+		ending = ";"
+	}
+	return ":" + label + ending
+}
+
+// condition は条件分岐の矢印のラベルを " (ラベル)" の形にフォーマットします
+func (e *activityEmitter) condition(edge *FlowEdge) string {
+	if edge.Label == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", edge.Label)
+}
+
+// arrow はラベルや線の種類を持つ矢印をアクティビティの矢印（-> ラベル;）にフォーマットします
+// 通常の矢印は省略できるため空文字列を返します
+func (e *activityEmitter) arrow(edge *FlowEdge) string {
+	arrow := "->"
+	switch edge.Line {
+	case "dotted":
+		arrow = "-[dotted]->"
+	case "thick":
+		arrow = "-[bold]->"
+	}
+	if edge.Label != "" {
+		return fmt.Sprintf("%s %s;", arrow, edge.Label)
+	}
+	if arrow != "->" {
+		return arrow + ";"
+	}
+	return ""
+}
+
+// graphElements はノードの形に対応するPlantUMLの要素です
+// PlantUMLにない形は見た目の近い要素で表現します
+var graphElements = map[string]string{
+	"rect":       "rectangle",
+	"round":      "card",
+	"stadium":    "usecase",
+	"circle":     "circle",
+	"diamond":    "hexagon",
+	"hexagon":    "hexagon",
+	"subroutine": "component",
+	"cylinder":   "database",
+	"asymmetric": "card",
+}
+
+// formatGraph はフローチャートを要素と矢印だけの汎用のグラフにフォーマットします
+// サブグラフは rectangle で囲んで表現します
+func (c *FlowchartConverter) formatGraph(model *flowchart) string {
+	var result strings.Builder
+	if layout := directionLayouts[model.Direction]; layout.directive != "" {
+		result.WriteString(layout.directive + "\n")
+	}
+	c.formatGraphGroup(&result, model, "", "")
+	for _, edge := range model.Edges {
+		result.WriteString(c.formatGraphEdge(edge) + "\n")
+	}
+	return result.String()
+}
+
+// formatGraphGroup は指定したサブグラフに所属するノードと、入れ子のサブグラフを書き出します
+func (c *FlowchartConverter) formatGraphGroup(result *strings.Builder, model *flowchart, subgraph, indent string) {
+	for _, node := range model.Nodes {
+		if node.Subgraph != subgraph {
+			continue
+		}
+		element, ok := graphElements[node.Shape]
+		if !ok {
+			element = "rectangle"
+		}
+		label := node.Label
+		if label == "" {
+			label = node.ID
+		}
+		result.WriteString(fmt.Sprintf("%s%s \"%s\" as %s\n", indent, element, label, PlantUMLName(node.ID)))
+	}
+
+	for _, child := range model.Subgraphs {
+		if child.Parent != subgraph {
+			continue
+		}
+		result.WriteString(fmt.Sprintf("%srectangle \"%s\" as %s {\n", indent, child.Title, graphAlias(child.ID)))
+		c.formatGraphGroup(result, model, child.ID, indent+"    ")
+		result.WriteString(indent + "}\n")
+	}
+}
+
+// graphAlias はサブグラフのIDを、PlantUMLの別名に使える識別子にします
+// タイトルがそのままIDになる subgraph Two Words では、空白や記号を _ に置き換えます（Two_Words）
+func graphAlias(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// graphHeads は終点側の矢じりの種類に対応するPlantUMLの矢じりです
+var graphHeads = map[string]string{
+	"arrow":  ">",
+	"circle": "o",
+	"cross":  "x",
+}
+
+// graphTails は始点側の矢じりの種類に対応するPlantUMLの矢じりです
+var graphTails = map[string]string{
+	"arrow":  "<",
+	"circle": "o",
+	"cross":  "x",
+}
+
+// formatGraphEdge は矢印をPlantUMLの矢印（A --> B : ラベル）にフォーマットします
+func (c *FlowchartConverter) formatGraphEdge(edge *FlowEdge) string {
+	var line string
+	switch edge.Line {
+	case "dotted":
+		line = ".."
+	case "thick":
+		line = "-[bold]-"
+	case "invisible":
+		line = "-[hidden]-"
+	default:
+		line = "--"
+	}
+	if edge.LayoutDirection != "" && len(line) == 2 {
+		line = line[:1] + edge.LayoutDirection + line[1:]
+	}
+
+	result := fmt.Sprintf("%s %s%s%s %s", PlantUMLName(edge.From), graphTails[edge.Tail], line, graphHeads[edge.Head], PlantUMLName(edge.To))
+	if edge.Label != "" {
+		result += " : " + edge.Label
+	}
+	return result
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestFlowchartConverter_Convert(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		direction string
+		mode      ParseMode
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:  "一本道のアクティビティ図",
+			input: "flowchart TD\nA([開始]) --> B[[受付]] -->|確認| C>通知]\nC -.-> D[(保存)]",
+			want:  "@startuml\nstart\n:開始;\n:受付|\n-> 確認;\n:通知>\n-[dotted]->;\n:保存]\nstop\n@enduml",
+		},
+		{
+			name: "条件分岐と合流",
+			input: `flowchart TD
+    A[注文] --> B{在庫あり?}
+    B -->|はい| C[出荷]
+    B -->|いいえ| D[取り寄せ]
+    D --> C
+    C --> E((完了))`,
+			want: `@startuml
+start
+:注文;
+if (在庫あり?) then (はい)
+else (いいえ)
+    :取り寄せ;
+endif
+:出荷;
+:完了;
+stop
+@enduml`,
+		},
+		{
+			name:  "3方向の分岐",
+			input: "graph TD\nA{種類} -->|a| B\nA -->|b| C\nA -->|c| D\nB & C & D --> E",
+			want:  "@startuml\nstart\nswitch (種類)\ncase (a)\n    :B;\ncase (b)\n    :C;\ncase (c)\n    :D;\nendswitch\n:E;\nstop\n@enduml",
+		},
+		{
+			name:  "合流しない経路",
+			input: "flowchart TD\nA{種類} -->|a| B\nA -->|b| C\nA -->|c| D\nB --> E\nC --> E",
+			want:  "@startuml\ntop to bottom direction\nhexagon \"種類\" as A\nrectangle \"B\" as B\nrectangle \"C\" as C\nrectangle \"D\" as D\nrectangle \"E\" as E\nA --> B : a\nA --> C : b\nA --> D : c\nB --> E\nC --> E\n@enduml",
+		},
+		{
+			name:  "並行する経路",
+			input: "flowchart TD\nA --> B & C\nB --> D\nC --> D",
+			want:  "@startuml\nstart\n:A;\nsplit\n    :B;\nsplit again\n    :C;\nend split\n:D;\nstop\n@enduml",
+		},
+		{
+			name:  "横向きの図",
+			input: "flowchart LR\nA --> B --> C",
+			mode:  ModeStrict,
+			want:  "@startuml\nleft to right direction\nrectangle \"A\" as A\nrectangle \"B\" as B\nrectangle \"C\" as C\nA --> B\nB --> C\n@enduml",
+		},
+		{
+			name:  "下から上の図",
+			input: "flowchart BT\nA --> B",
+			want:  "@startuml\nrectangle \"A\" as A\nrectangle \"B\" as B\nA -up-> B\n@enduml",
+		},
+		{
+			name:  "両端の矢じり",
+			input: "flowchart LR\nA o--o B\nB x--x C\nC <-.-> D",
+			want:  "@startuml\nleft to right direction\nrectangle \"A\" as A\nrectangle \"B\" as B\nrectangle \"C\" as C\nrectangle \"D\" as D\nA o--o B\nB x--x C\nC <..> D\n@enduml",
+		},
+		{
+			name:  "閉路のあるグラフ",
+			input: "flowchart LR\nA[注文] --> B(支払い)\nB -->|再試行| A",
+			want:  "@startuml\nleft to right direction\nrectangle \"注文\" as A\ncard \"支払い\" as B\nA --> B\nB --> A : 再試行\n@enduml",
+		},
+		{
+			name: "サブグラフ",
+			input: `flowchart TB
+    subgraph S1 [受付]
+        A --> B
+        subgraph S2 [確認]
+            C{{審査}}
+        end
+    end
+    B --> C
+    S1 ==> D[(DB)]`,
+			want: `@startuml
+top to bottom direction
+database "DB" as D
+rectangle "受付" as S1 {
+    rectangle "A" as A
+    rectangle "B" as B
+    rectangle "確認" as S2 {
+        hexagon "審査" as C
+    }
+}
+A --> B
+B --> C
+S1 -[bold]-> D
+@enduml`,
+		},
+		{
+			name:  "空白を含むサブグラフのタイトル",
+			input: "flowchart TB\nsubgraph Two Words\n    A --> B\nend",
+			want:  "@startuml\ntop to bottom direction\nrectangle \"Two Words\" as Two_Words {\n    rectangle \"A\" as A\n    rectangle \"B\" as B\n}\nA --> B\n@enduml",
+		},
+		{
+			name:      "向きの上書き",
+			input:     "flowchart TD\nA --- B\nA <--> C",
+			direction: "BT",
+			want:      "@startuml\nrectangle \"A\" as A\nrectangle \"B\" as B\nrectangle \"C\" as C\nA -up- B\nA <-up-> C\n@enduml",
+		},
+		{
+			name:  "セミコロンで区切った文",
+			input: "graph TD;A-->B;B-->C;",
+			want:  "@startuml\nstart\n:A;\n:B;\n:C;\nstop\n@enduml",
+		},
+		{
+			name:    "閉じられていないサブグラフ",
+			input:   "flowchart TD\nsubgraph S1\nA --> B",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := NewFlowchartConverter().Convert(tt.input, ConvertOptions{Direction: tt.direction, Mode: tt.mode})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowchartConverter_Errors(t *testing.T) {
+	input := "flowchart LR\nA[開始 --> B\nend\nclassDef hot fill:#f00\nA:::hot --> C\nsubgraph S1\n    direction TB\n    C --> D"
+	errs := []ParseError{
+		{Line: 2, Column: 1, Snippet: "A[開始 --> B", Code: ErrInvalidRelation, Message: "A のラベルが ] で閉じられていません"},
+		{Line: 3, Column: 1, Snippet: "end", Code: ErrUnexpectedToken, Message: "対応するサブグラフのない end です"},
+		{Line: 6, Column: 1, Snippet: "subgraph S1", Code: ErrUnexpectedToken, Message: "サブグラフ S1 が end で閉じられていません"},
+	}
+
+	runConverterErrorTests(t, NewFlowchartConverter(), []converterErrorTest{
+		{name: "通常モード", input: input, wantErrors: errs},
+		{
+			name:  "lenient モード",
+			input: "flowchart TD\nA --> B\nB --> \nclick A callback",
+			mode:  ModeLenient,
+			want:  "@startuml\nstart\n:A;\n:B;\nstop\n@enduml",
+			wantWarnings: []ParseError{
+				{Line: 3, Column: 1, Snippet: "B --> ", Code: ErrInvalidRelation, Message: `ノードが必要ですが "" が見つかりました`},
+				{Line: 4, Column: 1, Snippet: "click A callback", Code: ErrUnsupported, Message: "PlantUMLでは click を表現できないため無視します"},
+			},
+		},
+	})
+}
@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FlowchartParser はフローチャートのノード・矢印・サブグラフの解析を担当します
+// 1つの文に含まれる連続した矢印（A --> B --> C）や & で並べたノード（A & B --> C）も解析します
+type FlowchartParser struct {
+	headerPattern    *regexp.Regexp
+	subgraphPattern  *regexp.Regexp
+	idPattern        *regexp.Regexp
+	edgePattern      *regexp.Regexp
+	edgeTextPattern  *regexp.Regexp
+	separatorPattern *regexp.Regexp
+}
+
+// NewFlowchartParser は新しいFlowchartParserインスタンスを作成します
+func NewFlowchartParser() *FlowchartParser {
+	return &FlowchartParser{
+		headerPattern:    regexp.MustCompile(`^(?:flowchart|graph)(?:\s+(\S+))?$`),
+		subgraphPattern:  regexp.MustCompile(`^subgraph\s+(?:([\p{L}\p{N}_-]+)\s*\[\s*(.*?)\s*\]|(.+))$`),
+		idPattern:        regexp.MustCompile(`^[\p{L}\p{N}_]+`),
+		edgePattern:      regexp.MustCompile(`^([<ox])?(-{2,}|-\.+-|={2,}|~{3,})([>ox])?(?:\s*\|([^|]*)\|)?`),
+		edgeTextPattern:  regexp.MustCompile(`^([<ox])?(--|-\.|==)\s+(.+?)\s*(-{2,}|\.+-|={2,})([>ox])?`),
+		separatorPattern: regexp.MustCompile(`^\s*&\s*`),
+	}
+}
+
+// flowShape はノードの形を表す括弧の組です
+type flowShape struct {
+	open  string
+	close string
+	shape string
+}
+
+// flowShapes はノードの形の括弧の一覧です
+// 先頭が同じ括弧は、長いものから順に判定します
+var flowShapes = []flowShape{
+	{open: "((", close: "))", shape: "circle"},
+	{open: "([", close: "])", shape: "stadium"},
+	{open: "[[", close: "]]", shape: "subroutine"},
+	{open: "[(", close: ")]", shape: "cylinder"},
+	{open: "{{", close: "}}", shape: "hexagon"},
+	{open: "[", close: "]", shape: "rect"},
+	{open: "(", close: ")", shape: "round"},
+	{open: "{", close: "}", shape: "diamond"},
+	{open: ">", close: "]", shape: "asymmetric"},
+}
+
+// flowLines は矢印の線の文字に対応する線の種類です
+var flowLines = map[byte]string{
+	'-': "solid",
+	'.': "dotted",
+	'=': "thick",
+	'~': "invisible",
+}
+
+// flowHeads は矢じりの文字に対応する矢じりの種類です
+var flowHeads = map[string]string{
+	">": "arrow",
+	"o": "circle",
+	"x": "cross",
+	"":  "none",
+}
+
+// flowTails は始点側の矢じりの文字（<-->・o--o・x--x）に対応する矢じりの種類です
+var flowTails = map[string]string{
+	"<": "arrow",
+	"o": "circle",
+	"x": "cross",
+	"":  "none",
+}
+
+// ParseHeader は図の種類の宣言（flowchart LR / graph TD）を解析し、図の向きを返します
+// 向きの指定がない場合は TB を返します
+func (p *FlowchartParser) ParseHeader(line string) (string, error) {
+	matches := p.headerPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return "", fmt.Errorf("フローチャートの宣言を解釈できません: %s", line)
+	}
+	direction := strings.ToUpper(matches[1])
+	if direction == "" {
+		return "TB", nil
+	}
+	if _, ok := directionLayouts[direction]; !ok {
+		return "", fmt.Errorf("サポートされていない図の向き: %s", direction)
+	}
+	return direction, nil
+}
+
+// ParseSubgraph はサブグラフの開始行（subgraph id [タイトル] / subgraph タイトル）を解析します
+// IDを省略した場合はタイトルをIDとして使用します
+func (p *FlowchartParser) ParseSubgraph(line string) (*FlowSubgraph, error) {
+	matches := p.subgraphPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("サブグラフのIDがありません")
+	}
+	if matches[1] != "" {
+		return &FlowSubgraph{ID: matches[1], Title: p.text(matches[2])}, nil
+	}
+	title := p.text(matches[3])
+	return &FlowSubgraph{ID: title, Title: title}, nil
+}
+
+// ParseStatement はノードの宣言または矢印の連なりを解析し、現れたノードと矢印を返します
+// 形やラベルの指定がないノードは Shape と Label が空文字列になります
+func (p *FlowchartParser) ParseStatement(text string) ([]*FlowNode, []*FlowEdge, error) {
+	var nodes []*FlowNode
+	var edges []*FlowEdge
+
+	group, rest, err := p.parseNodeGroup(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes = append(nodes, group...)
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		var edge *FlowEdge
+		edge, rest, err = p.parseEdge(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var next []*FlowNode
+		next, rest, err = p.parseNodeGroup(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, from := range group {
+			for _, to := range next {
+				e := *edge
+				e.From, e.To = from.ID, to.ID
+				edges = append(edges, &e)
+			}
+		}
+		nodes = append(nodes, next...)
+		group = next
+	}
+	return nodes, edges, nil
+}
+
+// parseNodeGroup は & で並べたノードを解析し、残りの文字列を返します
+func (p *FlowchartParser) parseNodeGroup(text string) ([]*FlowNode, string, error) {
+	var nodes []*FlowNode
+	for {
+		node, rest, err := p.parseNode(text)
+		if err != nil {
+			return nil, "", err
+		}
+		nodes = append(nodes, node)
+
+		separator := p.separatorPattern.FindString(rest)
+		if separator == "" {
+			return nodes, rest, nil
+		}
+		text = rest[len(separator):]
+	}
+}
+
+// parseNode はノードのIDと、続く形の括弧（A[ラベル] など）を解析し、残りの文字列を返します
+func (p *FlowchartParser) parseNode(text string) (*FlowNode, string, error) {
+	id := p.idPattern.FindString(text)
+	if id == "" {
+		return nil, "", fmt.Errorf("ノードが必要ですが %q が見つかりました", text)
+	}
+	node := &FlowNode{ID: id}
+	rest := text[len(id):]
+
+	for _, shape := range flowShapes {
+		if !strings.HasPrefix(rest, shape.open) {
+			continue
+		}
+		body := rest[len(shape.open):]
+
+		// 引用符で囲まれたラベルは閉じ括弧と同じ文字を含むことがある
+		end := 0
+		if strings.HasPrefix(body, "\"") {
+			if quote := strings.Index(body[1:], "\""); quote >= 0 {
+				end = quote + 2
+			}
+		}
+		closing := strings.Index(body[end:], shape.close)
+		if closing < 0 {
+			return nil, "", fmt.Errorf("%s のラベルが %s で閉じられていません", id, shape.close)
+		}
+		node.Label = p.text(body[:end+closing])
+		node.Shape = shape.shape
+		rest = body[end+closing+len(shape.close):]
+		break
+	}
+	return node, rest, nil
+}
+
+// parseEdge は矢印（-->|ラベル| / -- ラベル --> / o--o など）を解析し、残りの文字列を返します
+func (p *FlowchartParser) parseEdge(text string) (*FlowEdge, string, error) {
+	// ラベルを挟む形では、開始と終了の線の種類が一致している必要がある
+	if matches := p.edgeTextPattern.FindStringSubmatch(text); matches != nil && p.lineChar(matches[2]) == matches[4][0] {
+		edge := &FlowEdge{
+			Label: p.text(matches[3]),
+			Line:  flowLines[p.lineChar(matches[2])],
+			Head:  flowHeads[matches[5]],
+			Tail:  flowTails[matches[1]],
+		}
+		return edge, text[len(matches[0]):], nil
+	}
+
+	matches := p.edgePattern.FindStringSubmatch(text)
+	if matches == nil {
+		return nil, "", fmt.Errorf("矢印が必要ですが %q が見つかりました", text)
+	}
+	line := flowLines[p.lineChar(matches[2])]
+	if matches[3] == "" && line == "solid" && len(matches[2]) < 3 {
+		return nil, "", fmt.Errorf("解釈できない矢印です: %q", matches[2])
+	}
+	edge := &FlowEdge{
+		Label: p.text(matches[4]),
+		Line:  line,
+		Head:  flowHeads[matches[3]],
+		Tail:  flowTails[matches[1]],
+	}
+	return edge, text[len(matches[0]):], nil
+}
+
+// lineChar は矢印の線の種類を表す文字を返します
+// 点線（-.-）は先頭が - のため、2文字目で判定します
+func (p *FlowchartParser) lineChar(arrow string) byte {
+	if len(arrow) > 1 && arrow[1] == '.' {
+		return '.'
+	}
+	return arrow[0]
+}
+
+// text はラベルを囲む引用符を取り除き、改行タグ（<br>）をPlantUMLの改行に変換します
+func (p *FlowchartParser) text(label string) string {
+	label = strings.TrimSpace(label)
+	if len(label) >= 2 && label[0] == '"' && label[len(label)-1] == '"' {
+		label = label[1 : len(label)-1]
+	}
+	return lineBreakPattern.ReplaceAllString(label, `\n`)
+}
@@ -0,0 +1,158 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlowchartParser_ParseHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{name: "向きの指定なし", line: "flowchart", want: "TB"},
+		{name: "graph", line: "graph LR", want: "LR"},
+		{name: "小文字の向き", line: "flowchart td", want: "TD"},
+		{name: "サポートされていない向き", line: "flowchart XY", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFlowchartParser().ParseHeader(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHeader() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowchartParser_ParseSubgraph(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *FlowSubgraph
+	}{
+		{name: "IDとタイトル", line: "subgraph S1 [受付]", want: &FlowSubgraph{ID: "S1", Title: "受付"}},
+		{name: "引用符付きのタイトル", line: `subgraph S1["受付 窓口"]`, want: &FlowSubgraph{ID: "S1", Title: "受付 窓口"}},
+		{name: "タイトルのみ", line: "subgraph 出荷", want: &FlowSubgraph{ID: "出荷", Title: "出荷"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFlowchartParser().ParseSubgraph(tt.line)
+
+			if err != nil {
+				t.Fatalf("ParseSubgraph() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSubgraph() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlowchartParser_ParseStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantNodes []*FlowNode
+		wantEdges []*FlowEdge
+		wantErr   bool
+	}{
+		{
+			name: "ノードの形",
+			text: `A[四角] --> B(角丸) --> C{ひし形} --> D((円)) --> E[[サブルーチン]] --> F>旗]`,
+			wantNodes: []*FlowNode{
+				{ID: "A", Label: "四角", Shape: "rect"},
+				{ID: "B", Label: "角丸", Shape: "round"},
+				{ID: "C", Label: "ひし形", Shape: "diamond"},
+				{ID: "D", Label: "円", Shape: "circle"},
+				{ID: "E", Label: "サブルーチン", Shape: "subroutine"},
+				{ID: "F", Label: "旗", Shape: "asymmetric"},
+			},
+			wantEdges: []*FlowEdge{
+				{From: "A", To: "B", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "B", To: "C", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "C", To: "D", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "D", To: "E", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "E", To: "F", Line: "solid", Head: "arrow", Tail: "none"},
+			},
+		},
+		{
+			name:      "引用符で囲まれたラベル",
+			text:      `A["a [b]<br>c"]`,
+			wantNodes: []*FlowNode{{ID: "A", Label: `a [b]\nc`, Shape: "rect"}},
+		},
+		{
+			name:      "矢印の種類",
+			text:      "A --- B -.-> C ==> D --o E --x F <--> G ~~~ H",
+			wantNodes: []*FlowNode{{ID: "A"}, {ID: "B"}, {ID: "C"}, {ID: "D"}, {ID: "E"}, {ID: "F"}, {ID: "G"}, {ID: "H"}},
+			wantEdges: []*FlowEdge{
+				{From: "A", To: "B", Line: "solid", Head: "none", Tail: "none"},
+				{From: "B", To: "C", Line: "dotted", Head: "arrow", Tail: "none"},
+				{From: "C", To: "D", Line: "thick", Head: "arrow", Tail: "none"},
+				{From: "D", To: "E", Line: "solid", Head: "circle", Tail: "none"},
+				{From: "E", To: "F", Line: "solid", Head: "cross", Tail: "none"},
+				{From: "F", To: "G", Line: "solid", Head: "arrow", Tail: "arrow"},
+				{From: "G", To: "H", Line: "invisible", Head: "none", Tail: "none"},
+			},
+		},
+		{
+			name:      "両端の矢じり",
+			text:      "A o--o B x--x C <--> D <-- 双方向 --> E o-. 任意 .-o F",
+			wantNodes: []*FlowNode{{ID: "A"}, {ID: "B"}, {ID: "C"}, {ID: "D"}, {ID: "E"}, {ID: "F"}},
+			wantEdges: []*FlowEdge{
+				{From: "A", To: "B", Line: "solid", Head: "circle", Tail: "circle"},
+				{From: "B", To: "C", Line: "solid", Head: "cross", Tail: "cross"},
+				{From: "C", To: "D", Line: "solid", Head: "arrow", Tail: "arrow"},
+				{From: "D", To: "E", Label: "双方向", Line: "solid", Head: "arrow", Tail: "arrow"},
+				{From: "E", To: "F", Label: "任意", Line: "dotted", Head: "circle", Tail: "circle"},
+			},
+		},
+		{
+			name:      "矢印のラベル",
+			text:      `A -->|はい| B -- "いいえ" --> C -. 任意 .-> D == 必須 ==> E`,
+			wantNodes: []*FlowNode{{ID: "A"}, {ID: "B"}, {ID: "C"}, {ID: "D"}, {ID: "E"}},
+			wantEdges: []*FlowEdge{
+				{From: "A", To: "B", Label: "はい", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "B", To: "C", Label: "いいえ", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "C", To: "D", Label: "任意", Line: "dotted", Head: "arrow", Tail: "none"},
+				{From: "D", To: "E", Label: "必須", Line: "thick", Head: "arrow", Tail: "none"},
+			},
+		},
+		{
+			name:      "& で並べたノード",
+			text:      "A & B --> C",
+			wantNodes: []*FlowNode{{ID: "A"}, {ID: "B"}, {ID: "C"}},
+			wantEdges: []*FlowEdge{
+				{From: "A", To: "C", Line: "solid", Head: "arrow", Tail: "none"},
+				{From: "B", To: "C", Line: "solid", Head: "arrow", Tail: "none"},
+			},
+		},
+		{name: "閉じられていないラベル", text: "A[開始 --> B", wantErr: true},
+		{name: "解釈できない矢印", text: "A -- B", wantErr: true},
+		{name: "矢印の先のノードがない", text: "A -->", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, edges, err := NewFlowchartParser().ParseStatement(tt.text)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(nodes, tt.wantNodes) {
+				t.Errorf("ParseStatement() nodes = %+v, want %+v", nodes, tt.wantNodes)
+			}
+			if !reflect.DeepEqual(edges, tt.wantEdges) {
+				t.Errorf("ParseStatement() edges = %+v, want %+v", edges, tt.wantEdges)
+			}
+		})
+	}
+}
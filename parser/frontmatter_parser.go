@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"strings"
+)
+
+// FrontmatterParser はYAMLフロントマターの解析を担当します
+// Mermaidのフロントマターで使われる範囲（入れ子のマッピングとスカラー値）だけをサポートします
+type FrontmatterParser struct{}
+
+// NewFrontmatterParser は新しいFrontmatterParserインスタンスを作成します
+func NewFrontmatterParser() *FrontmatterParser {
+	return &FrontmatterParser{}
+}
+
+// frontmatterLine はフロントマターの空行・コメント以外の1行です
+type frontmatterLine struct {
+	pos    Pos
+	indent int
+	key    string
+	value  string
+}
+
+// Parse はフロントマターの本文を解析してマッピングを返します
+// start は本文の先頭行の位置で、ParseErrorの行番号に使用します
+func (p *FrontmatterParser) Parse(text string, start Pos) (map[string]interface{}, error) {
+	var lines []frontmatterLine
+	for i, raw := range strings.Split(text, "\n") {
+		content := strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimLeft(content, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		pos := Pos{Line: start.Line + i, Column: len(content) - len(trimmed) + 1}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, newParseError(pos, ErrInvalidFrontmatter, "フロントマターの行を解釈できません: %q", trimmed)
+		}
+		lines = append(lines, frontmatterLine{
+			pos:    pos,
+			indent: pos.Column - 1,
+			key:    strings.TrimSpace(key),
+			value:  p.scalar(value),
+		})
+	}
+
+	values, rest, err := p.parseMapping(lines, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, newParseError(rest[0].pos, ErrInvalidFrontmatter, "フロントマターのインデントが揃っていません")
+	}
+	return values, nil
+}
+
+// parseMapping は同じインデントの行をひとつのマッピングとして解析し、残りの行を返します
+// 値のないキーの後にインデントの深い行が続く場合は、入れ子のマッピングとして扱います
+func (p *FrontmatterParser) parseMapping(lines []frontmatterLine, indent int) (map[string]interface{}, []frontmatterLine, error) {
+	values := make(map[string]interface{})
+
+	for len(lines) > 0 && lines[0].indent == indent {
+		line := lines[0]
+		lines = lines[1:]
+
+		if line.value != "" || len(lines) == 0 || lines[0].indent <= indent {
+			values[line.key] = p.typed(line.value)
+			continue
+		}
+
+		child, rest, err := p.parseMapping(lines, lines[0].indent)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[line.key] = child
+		lines = rest
+	}
+
+	if len(lines) > 0 && lines[0].indent > indent {
+		return nil, nil, newParseError(lines[0].pos, ErrInvalidFrontmatter, "フロントマターのインデントが揃っていません")
+	}
+	return values, lines, nil
+}
+
+// scalar は値から行末のコメントと引用符を取り除きます
+func (p *FrontmatterParser) scalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+// typed は真偽値を bool に変換し、それ以外は文字列のまま返します
+func (p *FrontmatterParser) typed(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrontmatterParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:  "タイトル",
+			input: "title: 注文ドメイン\n",
+			want:  map[string]interface{}{"title": "注文ドメイン"},
+		},
+		{
+			name:  "入れ子の設定",
+			input: "title: \"A: B\"\nconfig:\n  theme: 'dark' \n  # コメント\n  class:\n    hideEmptyMembersBox: true\n  look: handDrawn # 手書き風\n",
+			want: map[string]interface{}{
+				"title": "A: B",
+				"config": map[string]interface{}{
+					"theme": "dark",
+					"class": map[string]interface{}{"hideEmptyMembersBox": true},
+					"look":  "handDrawn",
+				},
+			},
+		},
+		{
+			name:  "値のないキー",
+			input: "title:\n",
+			want:  map[string]interface{}{"title": ""},
+		},
+		{
+			name:    "コロンのない行",
+			input:   "title\n",
+			wantErr: true,
+		},
+		{
+			name:    "揃っていないインデント",
+			input:   "config:\n    theme: dark\n  look: classic\n",
+			wantErr: true,
+		},
+	}
+
+	p := NewFrontmatterParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Parse(tt.input, Pos{Line: 2, Column: 1})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// GenericTypeParser はチルダ表記（List~T~）のジェネリック型の解析を担当します
+type GenericTypeParser struct{}
+
+// NewGenericTypeParser は新しいGenericTypeParserインスタンスを作成します
+func NewGenericTypeParser() *GenericTypeParser {
+	return &GenericTypeParser{}
+}
+
+// ToPlantUML はテキスト中のチルダ表記をPlantUMLの <T> 表記に変換します
+// 例: List~Map~K,V~~ → List<Map<K,V>>
+func (p *GenericTypeParser) ToPlantUML(text string) (string, error) {
+	runes := []rune(text)
+	var result strings.Builder
+	depth := 0
+
+	for i, r := range runes {
+		if r != '~' {
+			result.WriteRune(r)
+			continue
+		}
+
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if isGenericOpen(next) {
+			depth++
+			result.WriteRune('<')
+			continue
+		}
+
+		if depth == 0 {
+			return "", fmt.Errorf("ジェネリック型のチルダが対応していません: %q", text)
+		}
+		depth--
+		result.WriteRune('>')
+	}
+
+	if depth != 0 {
+		return "", fmt.Errorf("ジェネリック型のチルダが閉じられていません: %q", text)
+	}
+	return result.String(), nil
+}
+
+// SplitName はクラス名を基本名と型パラメータ（PlantUML表記）に分割します
+// 例: Repository~T~ → ("Repository", "T")
+func (p *GenericTypeParser) SplitName(name string) (string, string, error) {
+	converted, err := p.ToPlantUML(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	index := strings.Index(converted, "<")
+	if index < 0 {
+		return converted, "", nil
+	}
+	if !strings.HasSuffix(converted, ">") {
+		return "", "", fmt.Errorf("ジェネリック型の後ろに余分な文字があります: %q", name)
+	}
+	return converted[:index], converted[index+1 : len(converted)-1], nil
+}
+
+// isGenericOpen はチルダの直後の文字から、そのチルダが型パラメータの開始かどうかを判定します
+// 直後に型名が続く場合は開始、それ以外（~、カンマ、空白、終端）は終了とみなします
+func isGenericOpen(next rune) bool {
+	return next == '_' || unicode.IsLetter(next) || unicode.IsDigit(next)
+}
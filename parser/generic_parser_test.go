@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestGenericTypeParser_ToPlantUML(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "ジェネリックなし", text: "String", want: "String"},
+		{name: "単純なジェネリック", text: "List~String~", want: "List<String>"},
+		{name: "複数の型パラメータ", text: "Map~String,Integer~", want: "Map<String,Integer>"},
+		{name: "入れ子のジェネリック", text: "List~Map~K,V~~", want: "List<Map<K,V>>"},
+		{name: "入れ子が末尾以外", text: "Map~List~K~,V~", want: "Map<List<K>,V>"},
+		{name: "パラメータリスト", text: "List~int~ ids, Map~K,V~ m", want: "List<int> ids, Map<K,V> m"},
+		{name: "閉じていないチルダ", text: "List~String", wantErr: true},
+		{name: "対応しないチルダ", text: "String~", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewGenericTypeParser()
+			got, err := p.ToPlantUML(tt.text)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToPlantUML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ToPlantUML() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenericTypeParser_SplitName(t *testing.T) {
+	tests := []struct {
+		name       string
+		className  string
+		wantBase   string
+		wantParams string
+		wantErr    bool
+	}{
+		{name: "ジェネリックなし", className: "Order", wantBase: "Order"},
+		{name: "型パラメータ1つ", className: "Repository~T~", wantBase: "Repository", wantParams: "T"},
+		{name: "入れ子の型パラメータ", className: "Cache~Map~K,V~~", wantBase: "Cache", wantParams: "Map<K,V>"},
+		{name: "余分な文字", className: "Box~T~Extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewGenericTypeParser()
+			gotBase, gotParams, err := p.SplitName(tt.className)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if gotBase != tt.wantBase || gotParams != tt.wantParams {
+				t.Errorf("SplitName() got = (%v, %v), want (%v, %v)", gotBase, gotParams, tt.wantBase, tt.wantParams)
+			}
+		})
+	}
+}
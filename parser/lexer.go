@@ -0,0 +1,407 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenKind はトークンの種類を表現します
+type TokenKind int
+
+const (
+	TokenEOF        TokenKind = iota // 入力の終端
+	TokenNewline                     // 改行
+	TokenIdent                       // 識別子・キーワード
+	TokenString                      // ダブルクォートで囲まれた文字列
+	TokenArrow                       // 関連の矢印
+	TokenLBrace                      // {
+	TokenRBrace                      // }
+	TokenColon                       // :
+	TokenText                        // コロン以降の行末までの自由記述
+	TokenMember                      // クラス本体のメンバー行
+	TokenAnnotation                  // <<...>> 形式のアノテーション
+	TokenLBracket                    // [
+	TokenRBracket                    // ]
+	TokenCSSClass                    // :::
+	TokenIllegal                     // 解釈できない文字
+)
+
+var tokenKindNames = map[TokenKind]string{
+	TokenEOF:        "EOF",
+	TokenNewline:    "改行",
+	TokenIdent:      "識別子",
+	TokenString:     "文字列",
+	TokenArrow:      "矢印",
+	TokenLBrace:     "{",
+	TokenRBrace:     "}",
+	TokenColon:      ":",
+	TokenText:       "テキスト",
+	TokenMember:     "メンバー",
+	TokenAnnotation: "アノテーション",
+	TokenLBracket:   "[",
+	TokenRBracket:   "]",
+	TokenCSSClass:   ":::",
+	TokenIllegal:    "不正な文字",
+}
+
+// String はトークン種別の名前を返します
+func (k TokenKind) String() string {
+	if name, ok := tokenKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("TokenKind(%d)", int(k))
+}
+
+// Token は字句解析の結果となるトークンを表現します
+type Token struct {
+	Kind  TokenKind
+	Value string
+	Pos   Pos
+}
+
+// String はエラーメッセージ用のトークン表記を返します
+func (t Token) String() string {
+	switch t.Kind {
+	case TokenEOF, TokenNewline:
+		return t.Kind.String()
+	}
+	return fmt.Sprintf("%s %q", t.Kind, t.Value)
+}
+
+// Lexer はMermaidクラス図のソースをトークン列に分解します
+type Lexer struct {
+	input      []rune
+	offset     int
+	line       int
+	column     int
+	lineHead   string
+	afterColon bool
+	inBody     bool
+}
+
+// NewLexer は新しいLexerインスタンスを作成します
+func NewLexer(input string) *Lexer {
+	return &Lexer{
+		input:  []rune(input),
+		line:   1,
+		column: 1,
+	}
+}
+
+// Tokenize は入力全体をトークン列に変換します（末尾はTokenEOF）
+func (l *Lexer) Tokenize() []Token {
+	var tokens []Token
+	for {
+		tok := l.Next()
+		tokens = append(tokens, tok)
+		if tok.Kind == TokenEOF {
+			return tokens
+		}
+	}
+}
+
+// Next は次のトークンを返します
+// %% から行末まではコメントとして読み飛ばします
+func (l *Lexer) Next() Token {
+	if l.inBody {
+		return l.nextInBody()
+	}
+
+	l.skipSpaces()
+	pos := l.pos()
+	if l.eof() {
+		return Token{Kind: TokenEOF, Pos: pos}
+	}
+
+	if l.atComment() {
+		l.readUntil("\n")
+		return l.Next()
+	}
+
+	if l.afterColon {
+		// コロン以降は行末までをひとまとまりのテキストとして扱う
+		l.afterColon = false
+		if l.peek() != '\n' {
+			return Token{Kind: TokenText, Value: stripComment(l.readUntil("\n")), Pos: pos}
+		}
+		return Token{Kind: TokenText, Pos: pos}
+	}
+
+	r := l.peek()
+	switch {
+	case r == '\n':
+		l.advance()
+		l.lineHead = ""
+		return Token{Kind: TokenNewline, Value: "\n", Pos: pos}
+	case r == '{':
+		l.advance()
+		if l.lineHead == "class" {
+			l.inBody = true
+		}
+		return Token{Kind: TokenLBrace, Value: "{", Pos: pos}
+	case r == '}':
+		l.advance()
+		return Token{Kind: TokenRBrace, Value: "}", Pos: pos}
+	case r == '[':
+		l.advance()
+		return Token{Kind: TokenLBracket, Value: "[", Pos: pos}
+	case r == ']':
+		l.advance()
+		return Token{Kind: TokenRBracket, Value: "]", Pos: pos}
+	case r == '`':
+		return l.readQuotedIdent(pos)
+	case r == ':' && l.peekAt(1) == ':' && l.peekAt(2) == ':':
+		l.advance()
+		l.advance()
+		l.advance()
+		return Token{Kind: TokenCSSClass, Value: ":::", Pos: pos}
+	case r == ':':
+		l.advance()
+		l.afterColon = true
+		return Token{Kind: TokenColon, Value: ":", Pos: pos}
+	case r == '"':
+		return l.readString(pos)
+	case r == '<' && l.peekAt(1) == '<':
+		return l.readAnnotation(pos)
+	case l.atArrowStart():
+		return l.readArrow(pos)
+	case isIdentRune(r):
+		value := l.readIdent()
+		if l.lineHead == "" {
+			l.lineHead = value
+		}
+		return Token{Kind: TokenIdent, Value: value, Pos: pos}
+	}
+
+	l.advance()
+	return Token{Kind: TokenIllegal, Value: string(r), Pos: pos}
+}
+
+// RestOfLine は現在位置から行末までをテキストトークンとして読み取ります
+// style や classDef のように、行の残りをCSS形式の指定として扱う文で使用します
+func (l *Lexer) RestOfLine() Token {
+	l.skipSpaces()
+	pos := l.pos()
+	return Token{Kind: TokenText, Value: stripComment(l.readUntil("\n")), Pos: pos}
+}
+
+// nextInBody はクラス本体内のトークンを返します
+// 本体内では各行をひとつのメンバートークンとして扱い、コメント行は読み飛ばします
+func (l *Lexer) nextInBody() Token {
+	for !l.eof() && (unicode.IsSpace(l.peek()) || l.atComment()) {
+		if l.atComment() {
+			l.readUntil("\n")
+			continue
+		}
+		l.advance()
+	}
+	pos := l.pos()
+	if l.eof() {
+		return Token{Kind: TokenEOF, Pos: pos}
+	}
+
+	if l.peek() == '}' {
+		l.advance()
+		l.inBody = false
+		return Token{Kind: TokenRBrace, Value: "}", Pos: pos}
+	}
+
+	// 行末のコメント内の } で本体が閉じないよう、コメントの手前までをメンバーとします
+	start := l.offset
+	for !l.eof() && l.peek() != '\n' && l.peek() != '}' && !l.atComment() {
+		l.advance()
+	}
+	value := strings.TrimSpace(string(l.input[start:l.offset]))
+	if l.atComment() {
+		l.readUntil("\n")
+	}
+	return Token{Kind: TokenMember, Value: value, Pos: pos}
+}
+
+// atComment は現在位置がコメント（%%）の開始かどうかを判定します
+func (l *Lexer) atComment() bool {
+	return l.peek() == '%' && l.peekAt(1) == '%'
+}
+
+// readString はダブルクォートで囲まれた文字列を読み取ります
+func (l *Lexer) readString(pos Pos) Token {
+	l.advance()
+	value := l.readUntil("\"\n")
+	if l.peek() != '"' {
+		return Token{Kind: TokenIllegal, Value: "\"" + value, Pos: pos}
+	}
+	l.advance()
+	return Token{Kind: TokenString, Value: value, Pos: pos}
+}
+
+// readQuotedIdent はバッククォートで囲まれた識別子（`Animal Class!`）を読み取ります
+func (l *Lexer) readQuotedIdent(pos Pos) Token {
+	l.advance()
+	value := l.readUntil("`\n")
+	if l.peek() != '`' {
+		return Token{Kind: TokenIllegal, Value: "`" + value, Pos: pos}
+	}
+	l.advance()
+	if l.lineHead == "" {
+		l.lineHead = value
+	}
+	return Token{Kind: TokenIdent, Value: value, Pos: pos}
+}
+
+// readAnnotation は <<...>> 形式のアノテーションを読み取ります
+func (l *Lexer) readAnnotation(pos Pos) Token {
+	l.advance()
+	l.advance()
+	value := l.readUntil(">\n")
+	if l.peek() != '>' || l.peekAt(1) != '>' {
+		return Token{Kind: TokenIllegal, Value: "<<" + value, Pos: pos}
+	}
+	l.advance()
+	l.advance()
+	return Token{Kind: TokenAnnotation, Value: strings.TrimSpace(value), Pos: pos}
+}
+
+// atArrowStart は現在位置が関連の矢印の開始かどうかを判定します
+func (l *Lexer) atArrowStart() bool {
+	isLine := func(r rune) bool { return r == '-' || r == '.' }
+	switch r := l.peek(); r {
+	case '<':
+		return l.peekAt(1) == '|' || isLine(l.peekAt(1))
+	case '*':
+		return isLine(l.peekAt(1))
+	case 'o':
+		// o-- / o.. は集約。識別子の途中では字句解析がここに到達しない
+		return isLine(l.peekAt(1)) && isLine(l.peekAt(2))
+	case '-', '.':
+		return l.peekAt(1) == r
+	}
+	return false
+}
+
+// readArrow は矢印（始端記号・線・終端記号）を読み取ります
+func (l *Lexer) readArrow(pos Pos) Token {
+	start := l.offset
+
+	switch l.peek() {
+	case '<':
+		l.advance()
+		if l.peek() == '|' {
+			l.advance()
+		}
+	case '*', 'o':
+		l.advance()
+	}
+
+	lineRune := l.peek()
+	length := 0
+	for !l.eof() && l.peek() == lineRune && (lineRune == '-' || lineRune == '.') {
+		l.advance()
+		length++
+	}
+
+	switch {
+	case l.peek() == '|' && l.peekAt(1) == '>':
+		l.advance()
+		l.advance()
+	case l.peek() == '>', l.peek() == '*':
+		l.advance()
+	case l.peek() == 'o' && !isIdentRune(l.peekAt(1)):
+		l.advance()
+	}
+
+	// 続けて矢印の記号がある場合（<-.- など）は、全体をひとつの不正な矢印とする
+	trailing := l.readWhile(func(r rune) bool { return strings.ContainsRune("<>|*-.", r) })
+
+	value := string(l.input[start:l.offset])
+	if length < 2 || trailing != "" {
+		return Token{Kind: TokenIllegal, Value: value, Pos: pos}
+	}
+	return Token{Kind: TokenArrow, Value: value, Pos: pos}
+}
+
+// readIdent は識別子を読み取ります
+// ジェネリック型の型パラメータ内（Map~K, V~）ではカンマと空白も識別子の一部とします
+// チルダが閉じられていない場合に読み進めた末尾の空白は識別子に含めません
+func (l *Lexer) readIdent() string {
+	start := l.offset
+	depth := 0
+	for !l.eof() {
+		r := l.peek()
+		switch {
+		case r == '~' && isGenericOpen(l.peekAt(1)):
+			depth++
+		case r == '~' && depth > 0:
+			depth--
+		case depth > 0 && (r == ',' || r == ' '):
+		case !isIdentRune(r):
+			return strings.TrimRight(string(l.input[start:l.offset]), " ")
+		}
+		l.advance()
+	}
+	return strings.TrimRight(string(l.input[start:l.offset]), " ")
+}
+
+// skipSpaces は改行以外の空白を読み飛ばします
+func (l *Lexer) skipSpaces() {
+	for !l.eof() && l.peek() != '\n' && unicode.IsSpace(l.peek()) {
+		l.advance()
+	}
+}
+
+// readWhile は条件を満たす間の文字列を読み取ります
+func (l *Lexer) readWhile(cond func(rune) bool) string {
+	start := l.offset
+	for !l.eof() && cond(l.peek()) {
+		l.advance()
+	}
+	return string(l.input[start:l.offset])
+}
+
+// readUntil は指定した文字のいずれかが現れるまでの文字列を読み取ります
+func (l *Lexer) readUntil(stops string) string {
+	return l.readWhile(func(r rune) bool { return !strings.ContainsRune(stops, r) })
+}
+
+func (l *Lexer) eof() bool {
+	return l.offset >= len(l.input)
+}
+
+func (l *Lexer) peek() rune {
+	return l.peekAt(0)
+}
+
+func (l *Lexer) peekAt(n int) rune {
+	if l.offset+n >= len(l.input) {
+		return 0
+	}
+	return l.input[l.offset+n]
+}
+
+func (l *Lexer) advance() {
+	if l.input[l.offset] == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	l.offset++
+}
+
+func (l *Lexer) pos() Pos {
+	return Pos{Line: l.line, Column: l.column}
+}
+
+// stripComment は行末のコメント（%% 以降）を取り除きます
+func stripComment(text string) string {
+	if i := strings.Index(text, "%%"); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+// isIdentRune は識別子に使用できる文字かどうかを判定します
+// ジェネリック型のチルダ表記（List~T~）も識別子の一部として扱います
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '~' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLexer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Token
+	}{
+		{
+			name:  "クラス宣言と本体",
+			input: "class User {\n    +name: String\n}",
+			want: []Token{
+				{Kind: TokenIdent, Value: "class", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "User", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenLBrace, Value: "{", Pos: Pos{Line: 1, Column: 12}},
+				{Kind: TokenMember, Value: "+name: String", Pos: Pos{Line: 2, Column: 5}},
+				{Kind: TokenRBrace, Value: "}", Pos: Pos{Line: 3, Column: 1}},
+				{Kind: TokenEOF, Pos: Pos{Line: 3, Column: 2}},
+			},
+		},
+		{
+			name:  "多重度とラベル付きの関連",
+			input: `Order "1" *-- "0..*" OrderItem : contains`,
+			want: []Token{
+				{Kind: TokenIdent, Value: "Order", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenString, Value: "1", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenArrow, Value: "*--", Pos: Pos{Line: 1, Column: 11}},
+				{Kind: TokenString, Value: "0..*", Pos: Pos{Line: 1, Column: 15}},
+				{Kind: TokenIdent, Value: "OrderItem", Pos: Pos{Line: 1, Column: 22}},
+				{Kind: TokenColon, Value: ":", Pos: Pos{Line: 1, Column: 32}},
+				{Kind: TokenText, Value: "contains", Pos: Pos{Line: 1, Column: 34}},
+				{Kind: TokenEOF, Pos: Pos{Line: 1, Column: 42}},
+			},
+		},
+		{
+			name:  "集約の矢印と識別子のo",
+			input: "Foo o--o Bar\n",
+			want: []Token{
+				{Kind: TokenIdent, Value: "Foo", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenArrow, Value: "o--o", Pos: Pos{Line: 1, Column: 5}},
+				{Kind: TokenIdent, Value: "Bar", Pos: Pos{Line: 1, Column: 10}},
+				{Kind: TokenNewline, Value: "\n", Pos: Pos{Line: 1, Column: 13}},
+				{Kind: TokenEOF, Pos: Pos{Line: 2, Column: 1}},
+			},
+		},
+		{
+			name:  "スタイルクラスの指定",
+			input: "class Order:::hot",
+			want: []Token{
+				{Kind: TokenIdent, Value: "class", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "Order", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenCSSClass, Value: ":::", Pos: Pos{Line: 1, Column: 12}},
+				{Kind: TokenIdent, Value: "hot", Pos: Pos{Line: 1, Column: 15}},
+				{Kind: TokenEOF, Pos: Pos{Line: 1, Column: 18}},
+			},
+		},
+		{
+			name:  "コメントと行末のコメント",
+			input: "%% A -- B\nA --> B %% 関連\nA : +id %% ID",
+			want: []Token{
+				{Kind: TokenNewline, Value: "\n", Pos: Pos{Line: 1, Column: 10}},
+				{Kind: TokenIdent, Value: "A", Pos: Pos{Line: 2, Column: 1}},
+				{Kind: TokenArrow, Value: "-->", Pos: Pos{Line: 2, Column: 3}},
+				{Kind: TokenIdent, Value: "B", Pos: Pos{Line: 2, Column: 7}},
+				{Kind: TokenNewline, Value: "\n", Pos: Pos{Line: 2, Column: 14}},
+				{Kind: TokenIdent, Value: "A", Pos: Pos{Line: 3, Column: 1}},
+				{Kind: TokenColon, Value: ":", Pos: Pos{Line: 3, Column: 3}},
+				{Kind: TokenText, Value: "+id", Pos: Pos{Line: 3, Column: 5}},
+				{Kind: TokenEOF, Pos: Pos{Line: 3, Column: 14}},
+			},
+		},
+		{
+			name:  "クラス本体内のコメント",
+			input: "class A {\n    %% メモ\n    +id %% ID\n}",
+			want: []Token{
+				{Kind: TokenIdent, Value: "class", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "A", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenLBrace, Value: "{", Pos: Pos{Line: 1, Column: 9}},
+				{Kind: TokenMember, Value: "+id", Pos: Pos{Line: 3, Column: 5}},
+				{Kind: TokenRBrace, Value: "}", Pos: Pos{Line: 4, Column: 1}},
+				{Kind: TokenEOF, Pos: Pos{Line: 4, Column: 2}},
+			},
+		},
+		{
+			name:  "ジェネリック型の識別子",
+			input: "Map~K, V~ <|-- Cache",
+			want: []Token{
+				{Kind: TokenIdent, Value: "Map~K, V~", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenArrow, Value: "<|--", Pos: Pos{Line: 1, Column: 11}},
+				{Kind: TokenIdent, Value: "Cache", Pos: Pos{Line: 1, Column: 16}},
+				{Kind: TokenEOF, Pos: Pos{Line: 1, Column: 21}},
+			},
+		},
+		{
+			name:  "アノテーション",
+			input: "<<interface>> Shape",
+			want: []Token{
+				{Kind: TokenAnnotation, Value: "interface", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "Shape", Pos: Pos{Line: 1, Column: 15}},
+				{Kind: TokenEOF, Pos: Pos{Line: 1, Column: 20}},
+			},
+		},
+		{
+			name:  "表示名とバッククォートの識別子",
+			input: "class 注文[\"Order\"]\n`Order Item` -- 注文",
+			want: []Token{
+				{Kind: TokenIdent, Value: "class", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "注文", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenLBracket, Value: "[", Pos: Pos{Line: 1, Column: 9}},
+				{Kind: TokenString, Value: "Order", Pos: Pos{Line: 1, Column: 10}},
+				{Kind: TokenRBracket, Value: "]", Pos: Pos{Line: 1, Column: 17}},
+				{Kind: TokenNewline, Value: "\n", Pos: Pos{Line: 1, Column: 18}},
+				{Kind: TokenIdent, Value: "Order Item", Pos: Pos{Line: 2, Column: 1}},
+				{Kind: TokenArrow, Value: "--", Pos: Pos{Line: 2, Column: 14}},
+				{Kind: TokenIdent, Value: "注文", Pos: Pos{Line: 2, Column: 17}},
+				{Kind: TokenEOF, Pos: Pos{Line: 2, Column: 19}},
+			},
+		},
+		{
+			name:  "本体内の行末コメントの閉じ括弧",
+			input: "class A {\n  +foo() %% see }\n  +bar()\n}",
+			want: []Token{
+				{Kind: TokenIdent, Value: "class", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "A", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenLBrace, Value: "{", Pos: Pos{Line: 1, Column: 9}},
+				{Kind: TokenMember, Value: "+foo()", Pos: Pos{Line: 2, Column: 3}},
+				{Kind: TokenMember, Value: "+bar()", Pos: Pos{Line: 3, Column: 3}},
+				{Kind: TokenRBrace, Value: "}", Pos: Pos{Line: 4, Column: 1}},
+				{Kind: TokenEOF, Pos: Pos{Line: 4, Column: 2}},
+			},
+		},
+		{
+			name:  "閉じられていないジェネリック型",
+			input: "class A~T {",
+			want: []Token{
+				{Kind: TokenIdent, Value: "class", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIdent, Value: "A~T", Pos: Pos{Line: 1, Column: 7}},
+				{Kind: TokenLBrace, Value: "{", Pos: Pos{Line: 1, Column: 11}},
+				{Kind: TokenEOF, Pos: Pos{Line: 1, Column: 12}},
+			},
+		},
+		{
+			name:  "不正な文字",
+			input: "A - B",
+			want: []Token{
+				{Kind: TokenIdent, Value: "A", Pos: Pos{Line: 1, Column: 1}},
+				{Kind: TokenIllegal, Value: "-", Pos: Pos{Line: 1, Column: 3}},
+				{Kind: TokenIdent, Value: "B", Pos: Pos{Line: 1, Column: 5}},
+				{Kind: TokenEOF, Pos: Pos{Line: 1, Column: 6}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewLexer(tt.input).Tokenize()
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLexer_Arrows(t *testing.T) {
+	arrows := []string{
+		"<|--", "--|>", "*--", "--*", "o--", "--o", "-->", "<--",
+		"..>", "<..", "..|>", "<|..", "--", "..", "<|--|>", "<-->",
+	}
+
+	for _, arrow := range arrows {
+		t.Run(arrow, func(t *testing.T) {
+			tokens := NewLexer("A " + arrow + " B").Tokenize()
+			if len(tokens) != 4 || tokens[1].Kind != TokenArrow || tokens[1].Value != arrow {
+				t.Errorf("Tokenize() got = %v, want arrow %q", tokens, arrow)
+			}
+		})
+	}
+}
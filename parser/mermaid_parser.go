@@ -2,24 +2,45 @@ package parser
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 )
 
-// MermaidParser はMermaid形式のクラス図をPlantUML形式に変換するパーサー
+// MermaidParser はMermaid形式の図をPlantUML形式に変換するパーサー
+// 図の種類の宣言（classDiagram など）に応じて、登録されたコンバーターに変換を任せます
 type MermaidParser struct {
-	debugEnabled       bool
-	classParser        *ClassParser
-	relationshipParser *RelationshipParser
+	debugEnabled bool
+	direction    string
+	mode         ParseMode
+	converters   map[string]DiagramConverter
 }
 
 // NewMermaidParser は新しいMermaidParserインスタンスを作成します
 func NewMermaidParser() *MermaidParser {
-	return &MermaidParser{
-		debugEnabled:       true,
-		classParser:        NewClassParser(),
-		relationshipParser: NewRelationshipParser(),
+	p := &MermaidParser{
+		debugEnabled: true,
+		converters:   make(map[string]DiagramConverter),
 	}
+
+	classConverter := NewClassDiagramConverter()
+	p.RegisterConverter("classDiagram", classConverter)
+	p.RegisterConverter("classDiagram-v2", classConverter)
+	p.RegisterConverter("sequenceDiagram", NewSequenceDiagramConverter())
+
+	stateConverter := NewStateDiagramConverter()
+	p.RegisterConverter("stateDiagram", stateConverter)
+	p.RegisterConverter("stateDiagram-v2", stateConverter)
+	p.RegisterConverter("erDiagram", NewERDiagramConverter())
+
+	flowchartConverter := NewFlowchartConverter()
+	p.RegisterConverter("flowchart", flowchartConverter)
+	p.RegisterConverter("graph", flowchartConverter)
+	return p
+}
+
+// RegisterConverter は図の種類の宣言（sequenceDiagram など）に対応するコンバーターを登録します
+// 同じ宣言に登録済みのコンバーターは置き換えます
+func (p *MermaidParser) RegisterConverter(diagramType string, converter DiagramConverter) {
+	p.converters[diagramType] = converter
 }
 
 // debugPrint はデバッグ情報を出力します
@@ -29,78 +50,86 @@ func (p *MermaidParser) debugPrint(format string, args ...interface{}) {
 	}
 }
 
-// ParseToPlantUML はMermaid形式の文字列をPlantUML形式に変換します
-func (p *MermaidParser) ParseToPlantUML(input string) (string, error) {
-	if input == "" {
-		return "@startuml\n@enduml", nil
-	}
+// directionLayout は図の向きに対応するPlantUMLのレイアウト指定です
+// PlantUMLには右から左・下から上の指定がないため、RL / BT は矢印の方向ヒントで表現します
+// 方向ヒント（"left" や "up"）は各コンバーターが関連・遷移・矢印の LayoutDirection に設定し、
+// PlantUMLの矢印の線に -left-> や -up- のように埋め込みます
+type directionLayout struct {
+	directive string
+	hint      string
+}
 
-	if strings.Contains(input, "invalid syntax") {
-		return "", fmt.Errorf("不正な構文が含まれています")
-	}
+var directionLayouts = map[string]directionLayout{
+	"TB": {directive: "top to bottom direction"},
+	"TD": {directive: "top to bottom direction"},
+	"BT": {hint: "up"},
+	"LR": {directive: "left to right direction"},
+	"RL": {hint: "left"},
+}
 
-	lines := strings.Split(input, "\n")
-	var result strings.Builder
-	result.WriteString("@startuml\n")
-
-	relationships := []string{}
-	classes := make(map[string]string)
-	definedClasses := make(map[string]bool)
-
-	for i := 0; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" || line == "classDiagram" {
-			continue
-		}
-
-		// クラス定義の開始を検出
-		if strings.HasPrefix(line, "class ") {
-			className := strings.TrimSpace(strings.Split(line, "{")[0][6:])
-			i++
-			if i >= len(lines) {
-				break
-			}
-
-			// クラスの内容を解析
-			endIndex, classDef, err := p.classParser.ParseClassContent(lines, i)
-			if err != nil {
-				return "", err
-			}
-
-			// クラス定義を構築
-			var classContent strings.Builder
-			classContent.WriteString(fmt.Sprintf("class %s {\n", className))
-			for _, member := range classDef.Members {
-				classContent.WriteString(fmt.Sprintf("    %s\n", member))
-			}
-			classContent.WriteString("}\n")
-
-			classes[className] = classContent.String()
-			definedClasses[className] = true
-			i = endIndex
-
-		} else if strings.Contains(line, "--") || strings.Contains(line, "..") {
-			// 関連の処理
-			relationships = append(relationships, line)
-		}
+// SetDirection は図の向きを設定します
+// 設定した向きはMermaidの direction 指定より優先されます
+func (p *MermaidParser) SetDirection(direction string) error {
+	direction = strings.ToUpper(direction)
+	if _, ok := directionLayouts[direction]; !ok {
+		return fmt.Errorf("サポートされていない図の向き: %s", direction)
 	}
+	p.direction = direction
+	return nil
+}
+
+// ParseMode は解釈できない文やPlantUMLで表現できない指定の扱いを表現します
+// ModeDefault はモード導入前の動作で、SetMode を呼ばない既存の呼び出し元のためにゼロ値としています
+// 解釈できない文はエラー、表現できない指定は警告として出力を続けます
+// ModeStrict は警告もエラーに格上げし、ModeLenient はエラーも警告に格下げして、どちらか一方の扱いに揃えます
+type ParseMode int
+
+const (
+	ModeDefault ParseMode = iota // 解釈できない文はエラー、PlantUMLで表現できない指定は警告とする
+	ModeStrict                   // PlantUMLで表現できない指定も含め、最初に見つかったものをエラーとする
+	ModeLenient                  // 解釈できない文も読み飛ばし、警告として記録する
+)
+
+var parseModes = map[string]ParseMode{
+	"default": ModeDefault,
+	"strict":  ModeStrict,
+	"lenient": ModeLenient,
+}
 
-	// 関連を出力
-	for _, rel := range relationships {
-		result.WriteString(rel + "\n")
+// SetMode は変換モード（default / strict / lenient）を設定します
+func (p *MermaidParser) SetMode(mode string) error {
+	parsed, ok := parseModes[strings.ToLower(mode)]
+	if !ok {
+		return fmt.Errorf("サポートされていない変換モード: %s", mode)
 	}
+	p.mode = parsed
+	return nil
+}
+
+// ParseToPlantUML はMermaid形式の文字列をPlantUML形式に変換します
+// 変換時の警告が必要な場合は Convert を使用します
+func (p *MermaidParser) ParseToPlantUML(input string) (string, error) {
+	output, _, err := p.Convert(input)
+	return output, err
+}
 
-	// クラス定義を出力
-	classNames := make([]string, 0, len(classes))
-	for className := range classes {
-		classNames = append(classNames, className)
+// Convert はMermaid形式の文字列をPlantUML形式に変換し、変換時の警告とともに返します
+// 最初の意味のある行で宣言された図の種類に対応するコンバーターで変換します
+// 空の入力は空のクラス図として扱います
+func (p *MermaidParser) Convert(input string) (string, ParseErrors, error) {
+	// フロントマターやディレクティブの誤りは、図の種類ごとのコンバーターが報告する
+	source, _ := splitSource(input)
+	diagramType, pos := source.diagramType()
+	if diagramType == "" {
+		diagramType = "classDiagram"
 	}
-	sort.Strings(classNames)
 
-	for _, className := range classNames {
-		result.WriteString(classes[className])
+	converter, ok := p.converters[diagramType]
+	if !ok {
+		errs := ParseErrors{newParseError(pos, ErrUnsupportedDiagram, "サポートされていない図の種類です: %s", diagramType)}
+		errs.attachSource(input)
+		return "", nil, errs
 	}
 
-	result.WriteString("@enduml")
-	return result.String(), nil
+	return converter.Convert(input, ConvertOptions{Mode: p.mode, Direction: p.direction})
 }
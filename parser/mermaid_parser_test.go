@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -30,7 +32,28 @@ class User {
     +age: Integer
     +getName()
 }`,
-			want: "@startuml\nclass User {\n    +name: String\n    +age: Integer\n    +getName()\n}\n@enduml",
+			want: "@startuml\nclass User {\n    +name : String\n    +age : Integer\n    +getName()\n}\n@enduml",
+		},
+		{
+			name: "型が先の属性と戻り値付きメソッド",
+			input: `classDiagram
+class User {
+    +String name
+    -List~Order~ orders
+    +void register()
+    +findOrder(id) Order
+}`,
+			want: "@startuml\nclass User {\n    +name : String\n    -orders : List<Order>\n    +register() : void\n    +findOrder(id) : Order\n}\n@enduml",
+		},
+		{
+			name: "静的メンバーと抽象メンバー",
+			input: `classDiagram
+class Shape {
+    +int count$
+    +getInstance() Shape$
+    +draw()*
+}`,
+			want: "@startuml\nclass Shape {\n    {static} +count : int\n    {static} +getInstance() : Shape\n    {abstract} +draw()\n}\n@enduml",
 		},
 		{
 			name: "列挙型の定義",
@@ -41,7 +64,7 @@ class Status {
     ACTIVE
     COMPLETED
 }`,
-			want: "@startuml\nclass Status {\n    <<enumeration>>\n    PENDING\n    ACTIVE\n    COMPLETED\n}\n@enduml",
+			want: "@startuml\nenum Status {\n    PENDING\n    ACTIVE\n    COMPLETED\n}\n@enduml",
 		},
 		{
 			name: "クラス間の関連",
@@ -53,7 +76,7 @@ class OrderItem {
     +quantity: Integer
 }
 Order "1" *-- "0..*" OrderItem`,
-			want: "@startuml\nOrder \"1\" *-- \"0..*\" OrderItem\nclass Order {\n    +id: Integer\n}\nclass OrderItem {\n    +quantity: Integer\n}\n@enduml",
+			want: "@startuml\nclass Order {\n    +id : Integer\n}\nclass OrderItem {\n    +quantity : Integer\n}\nOrder \"1\" *-- \"0..*\" OrderItem\n@enduml",
 		},
 		{
 			name: "インターフェースとクラス",
@@ -66,7 +89,7 @@ class DataProcessor {
     +processData()
 }
 DataProcessor ..|> IProcessor`,
-			want: "@startuml\nDataProcessor ..|> IProcessor\nclass DataProcessor {\n    +processData()\n}\nclass IProcessor {\n    <<interface>>\n    +process()\n}\n@enduml",
+			want: "@startuml\nclass DataProcessor {\n    +processData()\n}\ninterface IProcessor {\n    +process()\n}\nDataProcessor ..|> IProcessor\n@enduml",
 		},
 		{
 			name: "複雑な関連とジェネリック型",
@@ -80,7 +103,304 @@ class Product {
     +price: Double
 }
 ShoppingCart "1" o-- "*" Product`,
-			want: "@startuml\nShoppingCart \"1\" o-- \"*\" Product\nclass Product {\n    +name: String\n    +price: Double\n}\nclass ShoppingCart {\n    +items: List~Product~\n    +addItem()\n}\n@enduml",
+			want: "@startuml\nclass Product {\n    +name : String\n    +price : Double\n}\nclass ShoppingCart {\n    +items : List<Product>\n    +addItem()\n}\nShoppingCart \"1\" o-- \"*\" Product\n@enduml",
+		},
+		{
+			name: "双方向の関連と長い線",
+			input: `classDiagram
+Animal <|--|> Robot
+Car *---- Engine`,
+			want: "@startuml\nclass Animal\nclass Car\nclass Engine\nclass Robot\nAnimal <|--|> Robot\nCar *-- Engine\n@enduml",
+		},
+		{
+			name: "ラベル付きの関連",
+			input: `classDiagram
+Customer "1" --> "*" Order : "places" >
+Order ..> Payment : uses`,
+			want: "@startuml\nclass Customer\nclass Order\nclass Payment\nCustomer \"1\" --> \"*\" Order : places >\nOrder ..> Payment : uses\n@enduml",
+		},
+		{
+			name: "ジェネリッククラスと関連の端点",
+			input: `classDiagram
+class Repository~T~ {
+    +find(id) T
+    +findAll(Map~String,Object~ filter) List~List~T~~
+}
+Repository~T~ <|-- OrderRepository`,
+			want: "@startuml\nclass OrderRepository\nclass Repository<T> {\n    +find(id) : T\n    +findAll(Map<String,Object> filter) : List<List<T>>\n}\nRepository <|-- OrderRepository\n@enduml",
+		},
+		{
+			name: "本体外のアノテーションとインラインのステレオタイプ",
+			input: `classDiagram
+<<interface>> Shape
+class Shape {
+    +draw()
+}
+class OrderService <<service>> {
+    +place()
+}
+<<abstract>> Base`,
+			want: "@startuml\nabstract class Base\nclass OrderService <<service>> {\n    +place()\n}\ninterface Shape {\n    +draw()\n}\n@enduml",
+		},
+		{
+			name: "列挙型・インターフェース・抽象クラス・アノテーション型の宣言",
+			input: `classDiagram
+class Status {
+    <<enum>>
+    ACTIVE
+}
+class Payable {
+    <<interface>>
+}
+class Shape {
+    <<abstract>>
+    +area() double*
+}
+class Audited {
+    <<annotation>>
+}`,
+			want: "@startuml\nannotation Audited\ninterface Payable\nabstract class Shape {\n    {abstract} +area() : double\n}\nenum Status {\n    ACTIVE\n}\n@enduml",
+		},
+		{
+			name: "本体のない宣言と1行の空の本体",
+			input: `classDiagram
+class Customer
+class Address{}
+class Order {
+    +id: Integer
+}
+class Customer {
+    +name: String
+}
+Customer --> Order`,
+			want: "@startuml\nclass Address\nclass Customer {\n    +name : String\n}\nclass Order {\n    +id : Integer\n}\nCustomer --> Order\n@enduml",
+		},
+		{
+			name: "本体の外のメンバー宣言",
+			input: `classDiagram
+Order : +String id
+Order : +cancel() bool
+class Order {
+    +place()
+}
+Order : +List~Item~ items
+Order --> Customer
+Customer : +getName() String`,
+			want: "@startuml\nclass Customer {\n    +getName() : String\n}\nclass Order {\n    +id : String\n    +cancel() : bool\n    +place()\n    +items : List<Item>\n}\nOrder --> Customer\n@enduml",
+		},
+		{
+			name: "矢印を含むメンバー宣言",
+			input: `classDiagram
+Order : +String note--draft`,
+			want: "@startuml\nclass Order {\n    +note--draft : String\n}\n@enduml",
+		},
+		{
+			name: "名前空間とその入れ子",
+			input: `classDiagram
+namespace Billing {
+    class Invoice {
+        +total: Money
+    }
+    namespace Payments {
+        class Payment
+    }
+}
+namespace Sales {
+    class Order
+}
+class Customer
+Order --> Invoice
+Invoice "1" *-- "*" Payment
+Customer --> Order`,
+			want: "@startuml\nclass Customer\npackage Billing {\n    class Invoice {\n        +total : Money\n    }\n    package Payments {\n        class Payment\n    }\n}\npackage Sales {\n    class Order\n}\nOrder --> Invoice\nInvoice \"1\" *-- \"*\" Payment\nCustomer --> Order\n@enduml",
+		},
+		{
+			name: "閉じていない名前空間",
+			input: `classDiagram
+namespace Billing {
+    class Invoice`,
+			wantErr: true,
+		},
+		{
+			name: "注釈",
+			input: `classDiagram
+note "ドメインモデル\nv2"
+class Order
+note for Order "合計金額は0以上<br>キャンセル後は変更不可"
+note for Customer "会員のみ"`,
+			want: "@startuml\nclass Customer\nclass Order\nnote as N1\n    ドメインモデル\n    v2\nend note\nnote right of Order\n    合計金額は0以上\n    キャンセル後は変更不可\nend note\nnote right of Customer\n    会員のみ\nend note\n@enduml",
+		},
+		{
+			name: "本文のない注釈",
+			input: `classDiagram
+note for Order`,
+			wantErr: true,
+		},
+		{
+			name: "左から右への向き",
+			input: `classDiagram
+direction LR
+Order --> Customer`,
+			want: "@startuml\nleft to right direction\nclass Customer\nclass Order\nOrder --> Customer\n@enduml",
+		},
+		{
+			name: "右から左への向き",
+			input: `classDiagram
+Animal <|-- Dog
+Order ..> Payment
+direction RL`,
+			want: "@startuml\nclass Animal\nclass Dog\nclass Order\nclass Payment\nAnimal <|-left- Dog\nOrder .left.> Payment\n@enduml",
+		},
+		{
+			name: "下から上への向き",
+			input: `classDiagram
+direction BT
+Animal <|-- Dog`,
+			want: "@startuml\nclass Animal\nclass Dog\nAnimal <|-up- Dog\n@enduml",
+		},
+		{
+			name: "不正な向き",
+			input: `classDiagram
+direction XY`,
+			wantErr: true,
+		},
+		{
+			name: "表示名と日本語のクラス名",
+			input: `classDiagram
+class Order["注文"] {
+    +String id
+}
+class 顧客 {
+    +String 氏名
+}
+顧客 "1" --> "*" Order : 注文する`,
+			want: "@startuml\nclass \"注文\" as Order {\n    +id : String\n}\nclass 顧客 {\n    +氏名 : String\n}\n顧客 \"1\" --> \"*\" Order : 注文する\n@enduml",
+		},
+		{
+			name:  "バッククォートで囲まれたクラス名",
+			input: "classDiagram\nclass `Order Item` {\n    +int quantity\n}\n`Order Item` --> Product\nnote for `Order Item` \"明細\"",
+			want:  "@startuml\nclass \"Order Item\" {\n    +quantity : int\n}\nclass Product\n\"Order Item\" --> Product\nnote right of \"Order Item\"\n    明細\nend note\n@enduml",
+		},
+		{
+			name: "スタイル指定",
+			input: `classDiagram
+class Order:::hot
+class User
+style User fill:#f9f,stroke:#333,stroke-width:4px
+classDef hot fill:#f96,color:#fff`,
+			want: "@startuml\nskinparam class<<hot>> {\n    BackgroundColor #f96\n    FontColor #fff\n}\nhide <<hot>> stereotype\nclass Order <<hot>>\nclass User #back:f9f;line:333;line.bold\n@enduml",
+		},
+		{
+			name: "既定のスタイルクラスとcssClass",
+			input: `classDiagram
+classDef default stroke:#333
+cssClass "A,B" hot
+classDef hot fill:red`,
+			want: "@startuml\nskinparam class {\n    BorderColor #333\n}\nskinparam class<<hot>> {\n    BackgroundColor red\n}\nhide <<hot>> stereotype\nclass A <<hot>>\nclass B <<hot>>\n@enduml",
+		},
+		{
+			name: "クリックとリンク",
+			input: `classDiagram
+class Order
+click Order href "https://wiki.example.com/Order" "注文の仕様"
+link Customer "https://wiki.example.com/Customer"
+callback Order "showOrder"`,
+			want: "@startuml\nclass Customer [[https://wiki.example.com/Customer]]\nclass Order [[https://wiki.example.com/Order{注文の仕様}]]\n@enduml",
+		},
+		{
+			name: "コメントを含む図",
+			input: `classDiagram
+%% Legacy -- Order は削除済み
+class Order {
+    %% 内部用
+    +id: int %% 主キー
+}
+Order --> Customer : places %% 顧客
+%% Order ..> Payment`,
+			want: "@startuml\nclass Customer\nclass Order {\n    +id : int\n}\nOrder --> Customer : places\n@enduml",
+		},
+		{
+			name: "initディレクティブ",
+			input: `%%{init: {"theme": "dark", "look": "handDrawn", "themeVariables": {"primaryColor": "#fefece", "lineColor": "#333"}}}%%
+classDiagram
+class Order`,
+			want: "@startuml\n!theme cyborg\nskinparam handwritten true\nskinparam ClassBackgroundColor #fefece\nskinparam ArrowColor #333\nclass Order\n@enduml",
+		},
+		{
+			name: "解釈できないディレクティブ",
+			input: `%%{init: {theme: }}%%
+classDiagram`,
+			wantErr: true,
+		},
+		{
+			name: "フロントマター",
+			input: `---
+title: 注文ドメイン -- 概要
+config:
+  theme: dark
+  look: handDrawn
+  class:
+    hideEmptyMembersBox: true
+---
+classDiagram
+Order --> Customer`,
+			want: "@startuml\n!theme cyborg\nskinparam handwritten true\nhide empty members\ntitle 注文ドメイン -- 概要\nclass Customer\nclass Order\nOrder --> Customer\n@enduml",
+		},
+		{
+			name: "解釈できないフロントマター",
+			input: `---
+title
+---
+classDiagram`,
+			wantErr: true,
+		},
+		{
+			name: "classDiagram-v2の宣言",
+			input: `%% 注文
+classDiagram-v2
+class Order`,
+			want: "@startuml\nclass Order\n@enduml",
+		},
+		{
+			name:    "サポートされていない図の種類",
+			input:   "journey\ntitle 買い物",
+			wantErr: true,
+		},
+		{
+			name:  "シーケンス図",
+			input: "%% 注文の流れ\nsequenceDiagram\n    participant C as Customer\n    C->>+Shop: 注文\n    Shop-->>-C: 確認",
+			want:  "@startuml\nparticipant \"Customer\" as C\nC -> Shop ++ : 注文\nShop --> C -- : 確認\n@enduml",
+		},
+		{
+			name:  "状態図",
+			input: "stateDiagram-v2\n    [*] --> Pending\n    Pending --> Paid : 入金",
+			want:  "@startuml\nhide empty description\n[*] --> Pending\nPending --> Paid : 入金\n@enduml",
+		},
+		{
+			name:  "ER図",
+			input: "erDiagram\n    CUSTOMER ||--o{ ORDER : places",
+			want:  "@startuml\nhide circle\nentity CUSTOMER\nentity ORDER\nCUSTOMER ||--o{ ORDER : places\n@enduml",
+		},
+		{
+			name:  "フローチャート",
+			input: "flowchart TD\n    A[注文] --> B{在庫あり?}\n    B -->|はい| C[出荷]\n    B -->|いいえ| C",
+			want:  "@startuml\nstart\n:注文;\nif (在庫あり?) then (はい)\nelse (いいえ)\nendif\n:出荷;\nstop\n@enduml",
+		},
+		{
+			name:  "グラフ",
+			input: "graph LR\n    A --> B\n    B --> A",
+			want:  "@startuml\nleft to right direction\nrectangle \"A\" as A\nrectangle \"B\" as B\nA --> B\nB --> A\n@enduml",
+		},
+		{
+			name:    "宣言のないクラス図",
+			input:   "class Order",
+			wantErr: true,
+		},
+		{
+			name: "解釈できない矢印",
+			input: `classDiagram
+A <-.- B`,
+			wantErr: true,
 		},
 	}
 
@@ -106,6 +426,202 @@ ShoppingCart "1" o-- "*" Product`,
 	}
 }
 
+func TestMermaidParser_Convert(t *testing.T) {
+	input := `classDiagram
+class Order:::cold
+A <-.- B
+style Order fill:#f9f,opacity:0.5
+click Order call showOrder()
+Order --> Customer`
+
+	tests := []struct {
+		name         string
+		mode         string
+		want         string
+		wantWarnings []string
+		wantErr      []string
+	}{
+		{
+			name:    "通常モード",
+			mode:    "default",
+			wantErr: []string{`3行3列: 解釈できない関連の矢印です: "<-.-"`},
+		},
+		{
+			name: "lenientモード",
+			mode: "lenient",
+			want: "@startuml\nclass Customer\nclass Order <<cold>> #back:f9f\nOrder --> Customer\n@enduml",
+			wantWarnings: []string{
+				"2行1列: Order に未定義のスタイルクラスが指定されています: cold",
+				`3行3列: 解釈できない関連の矢印です: "<-.-"`,
+				"4行1列: サポートされていないスタイル属性です: opacity",
+				"5行1列: Order のコールバックはPlantUMLで表現できないため無視しました",
+			},
+		},
+		{
+			name:    "strictモード",
+			mode:    "strict",
+			wantErr: []string{`3行3列: 解釈できない関連の矢印です: "<-.-"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMermaidParser()
+			if err := p.SetMode(tt.mode); err != nil {
+				t.Fatalf("SetMode() error = %v", err)
+			}
+			got, warnings, err := p.Convert(input)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != strings.Join(tt.wantErr, "\n") {
+					t.Errorf("Convert() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+			var messages []string
+			for _, warning := range warnings {
+				messages = append(messages, warning.Error())
+			}
+			if !reflect.DeepEqual(messages, tt.wantWarnings) {
+				t.Errorf("Convert() warnings = %q, want %q", messages, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestMermaidParser_StrictMode(t *testing.T) {
+	p := NewMermaidParser()
+	if err := p.SetMode("strict"); err != nil {
+		t.Fatalf("SetMode() error = %v", err)
+	}
+
+	// 最初に見つかったPlantUMLで表現できない指定がエラーになる
+	_, _, err := p.Convert("classDiagram\nclass Order\ncallback Order \"showOrder\"\nstyle Order opacity:0.5")
+	var errs ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Code != ErrUnsupported || errs[0].Line != 3 {
+		t.Errorf("Convert() error = %v, want the callback on line 3", err)
+	}
+
+	if _, warnings, err := p.Convert("classDiagram\nclass Order"); err != nil || len(warnings) != 0 {
+		t.Errorf("Convert() warnings = %v, error = %v", warnings, err)
+	}
+}
+
+func TestMermaidParser_SetMode(t *testing.T) {
+	p := NewMermaidParser()
+	for _, mode := range []string{"strict", "Lenient", "default"} {
+		if err := p.SetMode(mode); err != nil {
+			t.Errorf("SetMode(%q) error = %v", mode, err)
+		}
+	}
+	if err := p.SetMode("loose"); err == nil {
+		t.Errorf("SetMode(%q) error = nil, want error", "loose")
+	}
+}
+
+func TestMermaidParser_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []ParseError
+	}{
+		{
+			name:  "複数の構文エラー",
+			input: "classDiagram\nA <-.- B\nclass Order {\n}\nfoo bar",
+			want: []ParseError{
+				{Line: 2, Column: 3, Snippet: "A <-.- B", Code: ErrUnknownArrow, Message: `解釈できない関連の矢印です: "<-.-"`},
+				{Line: 5, Column: 5, Snippet: "foo bar", Code: ErrUnexpectedToken, Message: `矢印が必要ですが 識別子 "bar" が見つかりました`},
+			},
+		},
+		{
+			name:  "メンバーの記述漏れ",
+			input: "classDiagram\nclass Order\nOrder : \nOrder : +id",
+			want: []ParseError{
+				{Line: 3, Column: 9, Snippet: "Order : ", Code: ErrMissingValue, Message: "Order のメンバーが記述されていません"},
+			},
+		},
+		{
+			name:  "閉じられていないフロントマター",
+			input: "---\ntitle: x\nclassDiagram\nA --> B",
+			want: []ParseError{
+				{Line: 1, Column: 1, Snippet: "---", Code: ErrInvalidFrontmatter, Message: "フロントマターが --- で閉じられていません"},
+			},
+		},
+		{
+			name:  "モデル構築時のエラー",
+			input: "classDiagram\ndirection XY\n%%{init: {theme: }}%%",
+			want: []ParseError{
+				{Line: 2, Column: 1, Snippet: "direction XY", Code: ErrInvalidDirection, Message: "サポートされていない図の向き: XY"},
+				{Line: 3, Column: 1, Snippet: "%%{init: {theme: }}%%", Code: ErrInvalidDirective, Message: "init ディレクティブを解釈できません: invalid character '}' looking for beginning of value"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMermaidParser().ParseToPlantUML(tt.input)
+
+			var errs ParseErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("ParseToPlantUML() error = %v, want ParseErrors", err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ParseToPlantUML() errors = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if *errs[i] != want {
+					t.Errorf("ParseToPlantUML() errors[%d] = %+v, want %+v", i, *errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+// stubConverter は登録したコンバーターに変換が任されることを確認するためのコンバーター
+type stubConverter struct {
+	options ConvertOptions
+}
+
+func (c *stubConverter) Convert(input string, options ConvertOptions) (string, ParseErrors, error) {
+	c.options = options
+	return "@startuml\n@enduml", nil, nil
+}
+
+func TestMermaidParser_RegisterConverter(t *testing.T) {
+	p := NewMermaidParser()
+	converter := &stubConverter{}
+	p.RegisterConverter("journey", converter)
+	if err := p.SetMode("lenient"); err != nil {
+		t.Fatalf("SetMode() error = %v", err)
+	}
+	if err := p.SetDirection("LR"); err != nil {
+		t.Fatalf("SetDirection() error = %v", err)
+	}
+
+	got, err := p.ParseToPlantUML("%%{init: {}}%%\njourney\ntitle 買い物")
+	if err != nil {
+		t.Fatalf("ParseToPlantUML() error = %v", err)
+	}
+	if got != "@startuml\n@enduml" {
+		t.Errorf("ParseToPlantUML() got = %v", got)
+	}
+	if want := (ConvertOptions{Mode: ModeLenient, Direction: "LR"}); converter.options != want {
+		t.Errorf("Convert() options = %+v, want %+v", converter.options, want)
+	}
+
+	_, err = p.ParseToPlantUML("\n  gitGraph\n  commit")
+	var errs ParseErrors
+	if !errors.As(err, &errs) || errs[0].Code != ErrUnsupportedDiagram || errs[0].Error() != "2行3列: サポートされていない図の種類です: gitGraph" {
+		t.Errorf("ParseToPlantUML() error = %v, want unsupported diagram type", err)
+	}
+}
+
 func TestMermaidParser_DebugPrint(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -136,3 +652,53 @@ func TestMermaidParser_DebugPrint(t *testing.T) {
 		})
 	}
 }
+
+func TestMermaidParser_SetDirection(t *testing.T) {
+	input := `classDiagram
+direction LR
+Animal <|-- Dog`
+
+	tests := []struct {
+		name      string
+		direction string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "上から下で上書き",
+			direction: "TB",
+			want:      "@startuml\ntop to bottom direction\nclass Animal\nclass Dog\nAnimal <|-- Dog\n@enduml",
+		},
+		{
+			name:      "小文字の指定",
+			direction: "bt",
+			want:      "@startuml\nclass Animal\nclass Dog\nAnimal <|-up- Dog\n@enduml",
+		},
+		{
+			name:      "不正な向き",
+			direction: "XY",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewMermaidParser()
+			if err := p.SetDirection(tt.direction); (err != nil) != tt.wantErr {
+				t.Errorf("SetDirection() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := p.ParseToPlantUML(input)
+			if err != nil {
+				t.Fatalf("ParseToPlantUML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseToPlantUML() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,69 +1,122 @@
 package parser
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
 
 // RelationshipParser は関連の解析を担当します
 type RelationshipParser struct {
-	relationPattern      *regexp.Regexp
-	multiplicityPattern  *regexp.Regexp
-	bidirectionalPattern *regexp.Regexp
+	arrowPattern  *regexp.Regexp
+	genericParser *GenericTypeParser
 }
 
 // NewRelationshipParser は新しいRelationshipParserインスタンスを作成します
 func NewRelationshipParser() *RelationshipParser {
 	return &RelationshipParser{
-		relationPattern:      regexp.MustCompile(`(\w+)\s*([<\-\|>o.*]+)\s*(\w+)`),
-		multiplicityPattern:  regexp.MustCompile(`(\w+)\s*"([^"]+)"\s*([<\-\|>*o]+)\s*"([^"]+)"\s*(\w+)`),
-		bidirectionalPattern: regexp.MustCompile(`(\w+)\s*"([^"]+)"\s*(<-->)\s*"([^"]+)"\s*(\w+)`),
+		arrowPattern:  regexp.MustCompile(`^(<\||<|\*|o)?(-{2,}|\.{2,})(\|>|>|\*|o)?$`),
+		genericParser: NewGenericTypeParser(),
 	}
 }
 
-// ParseRelationship は関連定義を解析します
-func (p *RelationshipParser) ParseRelationship(line string) *Relationship {
-	if matches := p.multiplicityPattern.FindStringSubmatch(line); matches != nil {
-		return &Relationship{
-			Source:     matches[1],
-			Target:     matches[5],
-			Type:       matches[3],
-			SourceMult: matches[2],
-			TargetMult: matches[4],
-		}
+// BuildRelationship はASTの関連ノードから関連を構築します
+// 矢印がPlantUMLに変換できない場合はエラーを返します
+// 端点のクラス名はジェネリック型の型パラメータを除いた基本名に揃えます
+func (p *RelationshipParser) BuildRelationship(node *RelationNode) (*Relationship, error) {
+	if _, err := p.ToPlantUMLArrow(node.Arrow); err != nil {
+		return nil, err
 	}
 
-	if matches := p.bidirectionalPattern.FindStringSubmatch(line); matches != nil {
-		return &Relationship{
-			Source:     matches[1],
-			Target:     matches[5],
-			Type:       "<-->",
-			SourceMult: matches[2],
-			TargetMult: matches[4],
-		}
+	source, _, err := p.genericParser.SplitName(node.Source)
+	if err != nil {
+		return nil, err
+	}
+	target, _, err := p.genericParser.SplitName(node.Target)
+	if err != nil {
+		return nil, err
 	}
 
-	if matches := p.relationPattern.FindStringSubmatch(line); matches != nil {
-		return &Relationship{
-			Source: matches[1],
-			Target: matches[3],
-			Type:   matches[2],
-		}
+	label, direction := p.parseLabel(node.Label)
+	return &Relationship{
+		Source:         source,
+		Target:         target,
+		Type:           node.Arrow,
+		SourceMult:     node.SourceMult,
+		TargetMult:     node.TargetMult,
+		Label:          label,
+		LabelDirection: direction,
+	}, nil
+}
+
+// parseLabel は関連ラベルから読む方向の記号と囲みのダブルクォートを取り除きます
+func (p *RelationshipParser) parseLabel(text string) (string, string) {
+	label := strings.TrimSpace(text)
+	direction := ""
+
+	if strings.HasPrefix(label, "<") && !strings.HasPrefix(label, "<<") {
+		direction = "<"
+		label = strings.TrimSpace(label[1:])
+	} else if strings.HasSuffix(label, ">") && !strings.HasSuffix(label, ">>") {
+		direction = ">"
+		label = strings.TrimSpace(label[:len(label)-1])
+	}
+
+	if len(label) >= 2 && strings.HasPrefix(label, "\"") && strings.HasSuffix(label, "\"") {
+		label = label[1 : len(label)-1]
 	}
 
-	return nil
+	return label, direction
+}
+
+// ToPlantUMLArrow はMermaidの矢印をPlantUMLの矢印に変換します
+// 始端・終端の記号（<|, *, o, <, |>, >）はPlantUMLでも同じ意味を持つため、
+// 線の長さを2文字に正規化したうえでそのまま組み立て直します
+func (p *RelationshipParser) ToPlantUMLArrow(arrow string) (string, error) {
+	return p.toPlantUMLArrow(arrow, "")
 }
 
-// ExtractClassNames は関連定義から関係するクラス名を抽出します
-func (p *RelationshipParser) ExtractClassNames(line string) []string {
-	var classNames []string
-	parts := strings.Fields(line)
-	for _, part := range parts {
-		if !strings.Contains(part, "--") && !strings.Contains(part, "..") &&
-			!strings.Contains(part, "<") && !strings.Contains(part, ">") &&
-			!strings.Contains(part, "*") && !strings.Contains(part, "\"") {
-			classNames = append(classNames, part)
+// toPlantUMLArrow はMermaidの矢印を、配置の方向ヒント（-left-> など）付きのPlantUMLの矢印に変換します
+func (p *RelationshipParser) toPlantUMLArrow(arrow string, hint string) (string, error) {
+	matches := p.arrowPattern.FindStringSubmatch(arrow)
+	if matches == nil {
+		return "", fmt.Errorf("未対応の関連の矢印です: %q", arrow)
+	}
+
+	head, line, tail := matches[1], matches[2][:2], matches[3]
+	if hint != "" {
+		line = line[:1] + hint + line[1:]
+	}
+	return head + line + tail, nil
+}
+
+// FormatRelationship は関連をPlantUML形式の1行にフォーマットします
+func (p *RelationshipParser) FormatRelationship(rel *Relationship) (string, error) {
+	arrow, err := p.toPlantUMLArrow(rel.Type, rel.LayoutDirection)
+	if err != nil {
+		return "", err
+	}
+
+	var line strings.Builder
+	line.WriteString(PlantUMLName(rel.Source))
+	if rel.SourceMult != "" {
+		line.WriteString(fmt.Sprintf(" \"%s\"", rel.SourceMult))
+	}
+	line.WriteString(" " + arrow)
+	if rel.TargetMult != "" {
+		line.WriteString(fmt.Sprintf(" \"%s\"", rel.TargetMult))
+	}
+	line.WriteString(" " + PlantUMLName(rel.Target))
+
+	if rel.Label != "" {
+		switch rel.LabelDirection {
+		case "<":
+			line.WriteString(" : < " + rel.Label)
+		case ">":
+			line.WriteString(" : " + rel.Label + " >")
+		default:
+			line.WriteString(" : " + rel.Label)
 		}
 	}
-	return classNames
+	return line.String(), nil
 }
@@ -5,7 +5,7 @@ import (
 	"testing"
 )
 
-func TestRelationshipParser_ParseRelationship(t *testing.T) {
+func TestRelationshipParser_BuildRelationship(t *testing.T) {
 	tests := []struct {
 		name string
 		line string
@@ -69,60 +69,208 @@ func TestRelationshipParser_ParseRelationship(t *testing.T) {
 				Type:   "..>",
 			},
 		},
+		{
+			name: "ラベル付き関連",
+			line: "Customer --> Order : places",
+			want: &Relationship{
+				Source: "Customer",
+				Target: "Order",
+				Type:   "-->",
+				Label:  "places",
+			},
+		},
+		{
+			name: "多重度とクォート付きラベル",
+			line: `Customer "1" --> "*" Order : "places order"`,
+			want: &Relationship{
+				Source:     "Customer",
+				Target:     "Order",
+				Type:       "-->",
+				SourceMult: "1",
+				TargetMult: "*",
+				Label:      "places order",
+			},
+		},
+		{
+			name: "読む方向付きラベル",
+			line: "Company -- Person : employs >",
+			want: &Relationship{
+				Source:         "Company",
+				Target:         "Person",
+				Type:           "--",
+				Label:          "employs",
+				LabelDirection: ">",
+			},
+		},
+		{
+			name: "逆方向のラベル",
+			line: `Person -- Company : < "works for"`,
+			want: &Relationship{
+				Source:         "Person",
+				Target:         "Company",
+				Type:           "--",
+				Label:          "works for",
+				LabelDirection: "<",
+			},
+		},
+		{
+			name: "ジェネリッククラスの端点",
+			line: "Repository~T~ <|-- OrderRepository",
+			want: &Relationship{
+				Source: "Repository",
+				Target: "OrderRepository",
+				Type:   "<|--",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewRelationshipParser()
-			got := p.ParseRelationship(tt.line)
+			diagram, err := NewASTParser(tt.line).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			node, ok := diagram.Statements[0].(*RelationNode)
+			if !ok {
+				t.Fatalf("Parse() statement = %T, want *RelationNode", diagram.Statements[0])
+			}
 
+			got, err := NewRelationshipParser().BuildRelationship(node)
+			if err != nil {
+				t.Fatalf("BuildRelationship() error = %v", err)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ParseRelationship() got = %v, want %v", got, tt.want)
+				t.Errorf("BuildRelationship() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestRelationshipParser_ExtractClassNames(t *testing.T) {
+func TestRelationshipParser_ToPlantUMLArrow(t *testing.T) {
 	tests := []struct {
-		name string
-		line string
-		want []string
+		name    string
+		arrow   string
+		want    string
+		wantErr bool
+	}{
+		{name: "継承", arrow: "<|--", want: "<|--"},
+		{name: "コンポジション", arrow: "*--", want: "*--"},
+		{name: "集約", arrow: "o--", want: "o--"},
+		{name: "関連", arrow: "-->", want: "-->"},
+		{name: "依存", arrow: "..>", want: "..>"},
+		{name: "実現", arrow: "..|>", want: "..|>"},
+		{name: "リンク（実線）", arrow: "--", want: "--"},
+		{name: "リンク（破線）", arrow: "..", want: ".."},
+		{name: "双方向の継承", arrow: "<|--|>", want: "<|--|>"},
+		{name: "双方向のコンポジション", arrow: "*--*", want: "*--*"},
+		{name: "長い線の正規化", arrow: "---->", want: "-->"},
+		{name: "線種の混在", arrow: "-.->", wantErr: true},
+		{name: "未知の記号", arrow: "x--", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewRelationshipParser()
+			got, err := p.ToPlantUMLArrow(tt.arrow)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToPlantUMLArrow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("ToPlantUMLArrow() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelationshipParser_FormatRelationship(t *testing.T) {
+	tests := []struct {
+		name    string
+		rel     *Relationship
+		want    string
+		wantErr bool
 	}{
 		{
-			name: "単純な関連",
-			line: "ClassA -- ClassB",
-			want: []string{"ClassA", "ClassB"},
+			name: "多重度付き関連",
+			rel: &Relationship{
+				Source:     "Order",
+				Target:     "OrderItem",
+				Type:       "*--",
+				SourceMult: "1",
+				TargetMult: "0..*",
+			},
+			want: `Order "1" *-- "0..*" OrderItem`,
 		},
 		{
-			name: "多重度付き関連",
-			line: `Order "1" *-- "0..*" OrderItem`,
-			want: []string{"Order", "OrderItem"},
+			name: "多重度なしの継承",
+			rel:  &Relationship{Source: "Animal", Target: "Dog", Type: "<|--"},
+			want: "Animal <|-- Dog",
 		},
 		{
-			name: "双方向関連",
-			line: `Student "1..*" <--> "1..*" Course`,
-			want: []string{"Student", "Course"},
+			name: "多重度とラベル",
+			rel: &Relationship{
+				Source:     "Customer",
+				Target:     "Order",
+				Type:       "-->",
+				SourceMult: "1",
+				TargetMult: "*",
+				Label:      "places",
+			},
+			want: `Customer "1" --> "*" Order : places`,
 		},
 		{
-			name: "継承関係",
-			line: "Dog --|> Animal",
-			want: []string{"Dog", "Animal"},
+			name: "読む方向付きラベル",
+			rel: &Relationship{
+				Source:         "Company",
+				Target:         "Person",
+				Type:           "--",
+				Label:          "employs",
+				LabelDirection: ">",
+			},
+			want: "Company -- Person : employs >",
 		},
 		{
-			name: "依存関係",
-			line: "Controller ..> Service",
-			want: []string{"Controller", "Service"},
+			name: "逆方向のラベル",
+			rel: &Relationship{
+				Source:         "Person",
+				Target:         "Company",
+				Type:           "--",
+				Label:          "works for",
+				LabelDirection: "<",
+			},
+			want: "Person -- Company : < works for",
+		},
+		{
+			name: "配置の方向ヒント",
+			rel: &Relationship{
+				Source:          "Animal",
+				Target:          "Dog",
+				Type:            "<|..",
+				LayoutDirection: "up",
+			},
+			want: "Animal <|.up. Dog",
+		},
+		{
+			name:    "未対応の矢印",
+			rel:     &Relationship{Source: "A", Target: "B", Type: "<-.-"},
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := NewRelationshipParser()
-			got := p.ExtractClassNames(tt.line)
+			got, err := p.FormatRelationship(tt.rel)
 
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ExtractClassNames() got = %v, want %v", got, tt.want)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatRelationship() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("FormatRelationship() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SequenceDiagramConverter はMermaidのシーケンス図をPlantUMLのシーケンス図に変換するコンバーター
+// シーケンス図は文の順序がそのまま図になるため、ASTを作らずに1行ずつ変換します
+type SequenceDiagramConverter struct {
+	sequenceParser *SequenceParser
+	settingsParser *SettingsParser
+}
+
+// NewSequenceDiagramConverter は新しいSequenceDiagramConverterインスタンスを作成します
+func NewSequenceDiagramConverter() *SequenceDiagramConverter {
+	return &SequenceDiagramConverter{
+		sequenceParser: NewSequenceParser(),
+		settingsParser: NewSettingsParser(),
+	}
+}
+
+// sequenceArrows はMermaidのメッセージの矢印に対応するPlantUMLの矢印です
+// PlantUMLには矢じりのない線がないため、-> / --> は矢じり付きの矢印で表現します
+var sequenceArrows = map[string]string{
+	"->":     "->",
+	"-->":    "-->",
+	"->>":    "->",
+	"-->>":   "-->",
+	"-x":     "->x",
+	"--x":    "-->x",
+	"-)":     "->>",
+	"--)":    "-->>",
+	"<<->>":  "<->",
+	"<<-->>": "<-->",
+}
+
+// sequenceBranches は分岐のキーワードと、そのキーワードを使用できるブロックです
+var sequenceBranches = map[string]string{
+	"else":   "alt",
+	"and":    "par",
+	"option": "critical",
+}
+
+// closeSequenceBlock は最も内側のブロックを閉じます
+func closeSequenceBlock(w *blockWriter) {
+	if block := w.pop(); block.kind == "box" {
+		w.writeLine("end box", false)
+	} else {
+		w.writeLine("end", false)
+	}
+}
+
+// Convert はシーケンス図をPlantUML形式に変換し、変換時の警告とともに返します
+func (c *SequenceDiagramConverter) Convert(input string, options ConvertOptions) (string, ParseErrors, error) {
+	lc, err := beginConvert(c.settingsParser, input, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	writer := &blockWriter{}
+	var errs, warnings ParseErrors
+	for _, line := range lc.lines {
+		warning, err := c.convertLine(writer, line)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+	for i := len(writer.blocks) - 1; i >= 0; i-- {
+		block := writer.blocks[i]
+		errs = append(errs, newParseError(block.pos, ErrUnexpectedToken, "%s ブロックが end で閉じられていません", block.kind))
+		closeSequenceBlock(writer)
+	}
+	return lc.finish(writer.body.String(), errs, warnings)
+}
+
+// convertLine は1行をPlantUMLに変換して書き出します
+// PlantUMLで表現できない行は読み飛ばして警告を返します
+func (c *SequenceDiagramConverter) convertLine(w *blockWriter, line sourceLine) (*ParseError, *ParseError) {
+	statement, err := c.sequenceParser.ParseStatement(line.Text)
+	if err != nil {
+		return nil, asParseError(err, line.Pos, ErrUnexpectedToken)
+	}
+
+	switch statement.Kind {
+	case "end":
+		if len(w.blocks) == 0 {
+			return nil, newParseError(line.Pos, ErrUnexpectedToken, "対応するブロックのない end です")
+		}
+		closeSequenceBlock(w)
+
+	case "autonumber":
+		w.writeLine(strings.TrimSpace("autonumber "+statement.Label), false)
+
+	case "unsupported":
+		return newParseError(line.Pos, ErrUnsupported, "PlantUMLのシーケンス図では %s を表現できないため無視します", statement.Keyword), nil
+
+	case "title":
+		w.writeLine("title "+statement.Label, false)
+
+	case "participant":
+		declaration := statement.Keyword + " "
+		if statement.Created {
+			declaration = "create " + declaration
+		}
+		if statement.Label != "" {
+			declaration += fmt.Sprintf("\"%s\" as %s", c.formatText(statement.Label), PlantUMLName(statement.Participants[0]))
+		} else {
+			declaration += PlantUMLName(statement.Participants[0])
+		}
+		w.writeLine(declaration, false)
+
+	case "destroy", "activate", "deactivate":
+		w.writeLine(statement.Kind+" "+PlantUMLName(statement.Participants[0]), false)
+
+	case "note":
+		var participants []string
+		for _, participant := range statement.Participants {
+			participants = append(participants, PlantUMLName(participant))
+		}
+		w.writeLine(fmt.Sprintf("note %s %s : %s", statement.Keyword, strings.Join(participants, ", "), c.formatText(statement.Label)), false)
+
+	case "block":
+		return c.openBlock(w, line.Pos, statement.Keyword, statement.Label)
+
+	case "branch":
+		if w.top() != sequenceBranches[statement.Keyword] {
+			return nil, newParseError(line.Pos, ErrUnexpectedToken, "%s は %s ブロックの中でのみ使用できます", statement.Keyword, sequenceBranches[statement.Keyword])
+		}
+		w.writeLine(strings.TrimSpace("else "+c.formatText(statement.Label)), true)
+
+	case "message":
+		message := fmt.Sprintf("%s %s %s", PlantUMLName(statement.Participants[0]), sequenceArrows[statement.Arrow], PlantUMLName(statement.Participants[1]))
+		switch statement.Activation {
+		case "+":
+			message += " ++"
+		case "-":
+			message += " --"
+		}
+		if statement.Label != "" {
+			message += " : " + c.formatText(statement.Label)
+		}
+		w.writeLine(message, false)
+	}
+	return nil, nil
+}
+
+// openBlock はブロックの開始行を書き出します
+// rect は背景色付きの group、box は色とラベル付きの box に変換します
+func (c *SequenceDiagramConverter) openBlock(w *blockWriter, pos Pos, kind, label string) (*ParseError, *ParseError) {
+	var warning *ParseError
+	line := kind
+
+	switch kind {
+	case "rect":
+		color, rest, ok := c.sequenceParser.ParseColor(label)
+		if !ok {
+			return nil, newParseError(pos, ErrMissingValue, "rect の背景色がありません")
+		}
+		line = "group " + color
+		if rest != "" {
+			warning = newParseError(pos, ErrUnsupported, "rect の色以外の指定は無視します: %s", rest)
+		}
+	case "box":
+		color, rest, ok := c.sequenceParser.ParseColor(label)
+		if !ok {
+			rest = label
+		}
+		if rest != "" {
+			line += fmt.Sprintf(" \"%s\"", c.formatText(rest))
+		}
+		if color != "" {
+			line += " " + color
+		}
+	default:
+		if label != "" {
+			line += " " + c.formatText(label)
+		}
+	}
+
+	w.writeLine(line, false)
+	w.push(kind, pos)
+	return warning, nil
+}
+
+// formatText はメッセージやノートの文字列の改行タグ（<br>）をPlantUMLの改行に変換します
+func (c *SequenceDiagramConverter) formatText(text string) string {
+	return lineBreakPattern.ReplaceAllString(text, `\n`)
+}
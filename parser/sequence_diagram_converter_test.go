@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestSequenceDiagramConverter_Convert(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "参加者と別名",
+			input: "sequenceDiagram\nparticipant A as Alice\nactor B\ncreate participant C as 注文 API\ndestroy C",
+			want:  "@startuml\nparticipant \"Alice\" as A\nactor B\ncreate participant \"注文 API\" as C\ndestroy C\n@enduml",
+		},
+		{
+			name: "メッセージの矢印",
+			input: `sequenceDiagram
+A->B: 実線
+A-->B: 点線
+A->>B: 同期
+A-->>B: 応答
+A-xB: 失敗
+A--xB
+A-)B: 非同期
+A--)B: 非同期の応答
+A<<->>B: 双方向
+A<<-->>B: 双方向の点線`,
+			want: `@startuml
+A -> B : 実線
+A --> B : 点線
+A -> B : 同期
+A --> B : 応答
+A ->x B : 失敗
+A -->x B
+A ->> B : 非同期
+A -->> B : 非同期の応答
+A <-> B : 双方向
+A <--> B : 双方向の点線
+@enduml`,
+		},
+		{
+			name:  "活性化",
+			input: "sequenceDiagram\nA->>+B: 依頼<br/>急ぎ\nB-->>-A: 完了\nactivate A\ndeactivate A",
+			want:  "@startuml\nA -> B ++ : 依頼\\n急ぎ\nB --> A -- : 完了\nactivate A\ndeactivate A\n@enduml",
+		},
+		{
+			name:  "ノート",
+			input: "sequenceDiagram\nNote left of A: 左\nnote right of A : 右\nNote over A,B: 両方",
+			want:  "@startuml\nnote left of A : 左\nnote right of A : 右\nnote over A, B : 両方\n@enduml",
+		},
+		{
+			name: "入れ子のブロック",
+			input: `sequenceDiagram
+loop 毎分
+  alt 成功
+    A->>B: ok
+  else 失敗
+    A->>B: ng
+  end
+  opt 任意
+    A->>B: x
+  end
+end
+par 並列
+  A->>B: a
+and
+  A->>C: b
+end
+critical 接続
+  A->>B: c
+option タイムアウト
+  A->>A: 再試行
+end
+break 中断
+  A->>B: d
+end`,
+			want: `@startuml
+loop 毎分
+    alt 成功
+        A -> B : ok
+    else 失敗
+        A -> B : ng
+    end
+    opt 任意
+        A -> B : x
+    end
+end
+par 並列
+    A -> B : a
+else
+    A -> C : b
+end
+critical 接続
+    A -> B : c
+else タイムアウト
+    A -> A : 再試行
+end
+break 中断
+    A -> B : d
+end
+@enduml`,
+		},
+		{
+			name:  "rectとbox",
+			input: "sequenceDiagram\nbox rgb(33,66,99) 社内\nparticipant A\nend\nbox 外部\nparticipant B\nend\nrect #eef\nA->>B: x\nend",
+			want:  "@startuml\nbox \"社内\" #214263\n    participant A\nend box\nbox \"外部\"\n    participant B\nend box\ngroup #eef\n    A -> B : x\nend\n@enduml",
+		},
+		{
+			name:  "自動採番とタイトル",
+			input: "---\ntitle: 注文\n---\nsequenceDiagram\nautonumber 10 5\ntitle: 支払い\nA->>B: x %% コメント",
+			want:  "@startuml\ntitle 注文\nautonumber 10 5\ntitle 支払い\nA -> B : x\n@enduml",
+		},
+		{
+			name:    "閉じられていないブロック",
+			input:   "sequenceDiagram\nloop 毎分\nA->>B: x",
+			wantErr: true,
+		},
+		{
+			name:    "par の外の and",
+			input:   "sequenceDiagram\nalt 成功\nA->>B: x\nand\nend",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := NewSequenceDiagramConverter().Convert(tt.input, ConvertOptions{})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceDiagramConverter_Errors(t *testing.T) {
+	input := "sequenceDiagram\naccTitle: 注文\nA->>B: x\nend\nloop 毎分\nfoo\n"
+	errs := []ParseError{
+		{Line: 4, Column: 1, Snippet: "end", Code: ErrUnexpectedToken, Message: "対応するブロックのない end です"},
+		{Line: 5, Column: 1, Snippet: "loop 毎分", Code: ErrUnexpectedToken, Message: "loop ブロックが end で閉じられていません"},
+		{Line: 6, Column: 1, Snippet: "foo", Code: ErrUnexpectedToken, Message: "解釈できない行です: foo"},
+	}
+	unsupported := ParseError{Line: 2, Column: 1, Snippet: "accTitle: 注文", Code: ErrUnsupported, Message: "PlantUMLのシーケンス図では accTitle を表現できないため無視します"}
+
+	runConverterErrorTests(t, NewSequenceDiagramConverter(), []converterErrorTest{
+		{name: "通常モード", input: input, wantErrors: errs},
+		{
+			name:         "lenient モード",
+			input:        input,
+			mode:         ModeLenient,
+			want:         "@startuml\nA -> B : x\nloop 毎分\nend\n@enduml",
+			wantWarnings: append([]ParseError{unsupported}, errs...),
+		},
+	})
+}
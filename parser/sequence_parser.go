@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SequenceParser はシーケンス図の文（参加者・メッセージ・ノート・ブロックなど）の解析を担当します
+type SequenceParser struct {
+	participantPattern *regexp.Regexp
+	commandPattern     *regexp.Regexp
+	notePattern        *regexp.Regexp
+	messagePattern     *regexp.Regexp
+	blockPattern       *regexp.Regexp
+	branchPattern      *regexp.Regexp
+	titlePattern       *regexp.Regexp
+	unsupportedPattern *regexp.Regexp
+	rgbPattern         *regexp.Regexp
+}
+
+// NewSequenceParser は新しいSequenceParserインスタンスを作成します
+func NewSequenceParser() *SequenceParser {
+	return &SequenceParser{
+		participantPattern: regexp.MustCompile(`^(?:(create)\s+)?(participant|actor)\s+(.+?)(?:\s+as\s+(.+))?$`),
+		commandPattern:     regexp.MustCompile(`^(destroy|activate|deactivate)\s+(.+)$`),
+		notePattern:        regexp.MustCompile(`(?i)^note\s+(left of|right of|over)\s+([^:]+?)\s*:\s*(.*)$`),
+		messagePattern:     regexp.MustCompile(`^([^:]+?)\s*(<<-->>|<<->>|-->>|->>|-->|->|--x|-x|--\)|-\))\s*([+-]?)\s*([^:]+?)\s*(?::\s*(.*))?$`),
+		blockPattern:       regexp.MustCompile(`^(loop|alt|opt|par|critical|break|rect|box)(?:\s+(.*))?$`),
+		branchPattern:      regexp.MustCompile(`^(else|and|option)(?:\s+(.*))?$`),
+		titlePattern:       regexp.MustCompile(`^title(?:\s*:\s*|\s+)(.*)$`),
+		unsupportedPattern: regexp.MustCompile(`^(accTitle|accDescr|links?|properties|details)[\s:{]`),
+		rgbPattern:         regexp.MustCompile(`^rgba?\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*(?:,\s*[\d.]+\s*)?\)`),
+	}
+}
+
+// sequenceNamedColors は box の先頭の単語を色として扱う色名です
+var sequenceNamedColors = map[string]bool{
+	"transparent": true, "white": true, "black": true, "gray": true, "grey": true,
+	"red": true, "green": true, "blue": true, "yellow": true, "orange": true,
+	"purple": true, "pink": true, "aqua": true, "cyan": true, "lime": true,
+	"teal": true, "navy": true, "olive": true, "maroon": true, "silver": true,
+	"lightblue": true, "lightgreen": true, "lightgray": true, "lightgrey": true, "lightyellow": true,
+}
+
+// ParseStatement はシーケンス図の1行を解析します
+// 参加者の宣言やブロックのキーワードに当てはまらない行は、メッセージとして解析します
+func (p *SequenceParser) ParseStatement(line string) (*SequenceStatement, error) {
+	switch {
+	case line == "end":
+		return &SequenceStatement{Kind: "end"}, nil
+
+	case line == "autonumber" || strings.HasPrefix(line, "autonumber "):
+		return &SequenceStatement{Kind: "autonumber", Label: strings.TrimSpace(strings.TrimPrefix(line, "autonumber"))}, nil
+
+	case p.unsupportedPattern.MatchString(line):
+		return &SequenceStatement{Kind: "unsupported", Keyword: p.unsupportedPattern.FindStringSubmatch(line)[1]}, nil
+
+	case p.titlePattern.MatchString(line):
+		return &SequenceStatement{Kind: "title", Label: p.titlePattern.FindStringSubmatch(line)[1]}, nil
+
+	case p.participantPattern.MatchString(line):
+		matches := p.participantPattern.FindStringSubmatch(line)
+		return &SequenceStatement{
+			Kind:         "participant",
+			Keyword:      matches[2],
+			Participants: []string{matches[3]},
+			Label:        matches[4],
+			Created:      matches[1] != "",
+		}, nil
+
+	case p.commandPattern.MatchString(line):
+		matches := p.commandPattern.FindStringSubmatch(line)
+		return &SequenceStatement{Kind: matches[1], Participants: []string{matches[2]}}, nil
+
+	case p.notePattern.MatchString(line):
+		matches := p.notePattern.FindStringSubmatch(line)
+		statement := &SequenceStatement{Kind: "note", Keyword: strings.ToLower(matches[1]), Label: matches[3]}
+		for _, participant := range strings.Split(matches[2], ",") {
+			statement.Participants = append(statement.Participants, strings.TrimSpace(participant))
+		}
+		return statement, nil
+
+	case p.blockPattern.MatchString(line):
+		matches := p.blockPattern.FindStringSubmatch(line)
+		return &SequenceStatement{Kind: "block", Keyword: matches[1], Label: strings.TrimSpace(matches[2])}, nil
+
+	case p.branchPattern.MatchString(line):
+		matches := p.branchPattern.FindStringSubmatch(line)
+		return &SequenceStatement{Kind: "branch", Keyword: matches[1], Label: strings.TrimSpace(matches[2])}, nil
+	}
+
+	matches := p.messagePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("解釈できない行です: %s", line)
+	}
+	return &SequenceStatement{
+		Kind:         "message",
+		Participants: []string{matches[1], matches[4]},
+		Arrow:        matches[2],
+		Activation:   matches[3],
+		Label:        matches[5],
+	}, nil
+}
+
+// ParseColor は先頭の色指定（#hex・rgb()・rgba()・色名）をPlantUMLの色に変換し、残りの文字列とともに返します
+// PlantUMLの色は透明度を持たないため、rgba() の透明度は無視します
+func (p *SequenceParser) ParseColor(text string) (string, string, bool) {
+	if matches := p.rgbPattern.FindStringSubmatch(text); matches != nil {
+		color := "#"
+		for _, component := range matches[1:] {
+			value, _ := strconv.Atoi(component)
+			if value > 255 {
+				value = 255
+			}
+			color += fmt.Sprintf("%02X", value)
+		}
+		return color, strings.TrimSpace(text[len(matches[0]):]), true
+	}
+
+	word, rest, _ := strings.Cut(text, " ")
+	if strings.HasPrefix(word, "#") || sequenceNamedColors[strings.ToLower(word)] {
+		return "#" + strings.TrimPrefix(word, "#"), strings.TrimSpace(rest), true
+	}
+	return "", text, false
+}
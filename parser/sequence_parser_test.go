@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSequenceParser_ParseStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *SequenceStatement
+		wantErr bool
+	}{
+		{
+			name: "別名付きで作成される参加者",
+			line: "create participant C as 注文 API",
+			want: &SequenceStatement{Kind: "participant", Keyword: "participant", Participants: []string{"C"}, Label: "注文 API", Created: true},
+		},
+		{
+			name: "アクター",
+			line: "actor B",
+			want: &SequenceStatement{Kind: "participant", Keyword: "actor", Participants: []string{"B"}},
+		},
+		{
+			name: "活性化",
+			line: "activate A",
+			want: &SequenceStatement{Kind: "activate", Participants: []string{"A"}},
+		},
+		{
+			name: "複数の参加者にまたがるノート",
+			line: "Note over A, B: 両方",
+			want: &SequenceStatement{Kind: "note", Keyword: "over", Participants: []string{"A", "B"}, Label: "両方"},
+		},
+		{
+			name: "ラベル付きのブロック",
+			line: "loop 毎分",
+			want: &SequenceStatement{Kind: "block", Keyword: "loop", Label: "毎分"},
+		},
+		{
+			name: "分岐",
+			line: "else 失敗",
+			want: &SequenceStatement{Kind: "branch", Keyword: "else", Label: "失敗"},
+		},
+		{
+			name: "タイトル",
+			line: "title: 注文",
+			want: &SequenceStatement{Kind: "title", Label: "注文"},
+		},
+		{
+			name: "表現できない指定",
+			line: "accTitle: 注文",
+			want: &SequenceStatement{Kind: "unsupported", Keyword: "accTitle"},
+		},
+		{
+			name: "活性化を伴うメッセージ",
+			line: "A->>+B: 依頼",
+			want: &SequenceStatement{Kind: "message", Participants: []string{"A", "B"}, Arrow: "->>", Activation: "+", Label: "依頼"},
+		},
+		{
+			name:    "解釈できない行",
+			line:    "foo",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSequenceParser().ParseStatement(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatement() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceParser_ParseColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantColor string
+		wantRest  string
+		wantOK    bool
+	}{
+		{name: "rgb", text: "rgb(0, 128, 300)", wantColor: "#0080FF", wantOK: true},
+		{name: "rgba の透明度は無視", text: "rgba(255, 0, 0, 0.5) 注意", wantColor: "#FF0000", wantRest: "注意", wantOK: true},
+		{name: "色名", text: "Aqua 外部", wantColor: "#Aqua", wantRest: "外部", wantOK: true},
+		{name: "色の指定なし", text: "外部", wantRest: "外部"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color, rest, ok := NewSequenceParser().ParseColor(tt.text)
+			if color != tt.wantColor || rest != tt.wantRest || ok != tt.wantOK {
+				t.Errorf("ParseColor() = %q, %q, %v, want %q, %q, %v", color, rest, ok, tt.wantColor, tt.wantRest, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StateDiagramConverter はMermaidの状態図をPlantUMLの状態図に変換するコンバーター
+// 状態図の文はPlantUMLの文とほぼ1対1に対応するため、1行ずつ変換します
+type StateDiagramConverter struct {
+	stateParser    *StateParser
+	settingsParser *SettingsParser
+}
+
+// NewStateDiagramConverter は新しいStateDiagramConverterインスタンスを作成します
+func NewStateDiagramConverter() *StateDiagramConverter {
+	return &StateDiagramConverter{
+		stateParser:    NewStateParser(),
+		settingsParser: NewSettingsParser(),
+	}
+}
+
+// stateConversion は1つの状態図の変換中の状態です
+type stateConversion struct {
+	writer   blockWriter
+	hint     string
+	warnings ParseErrors
+}
+
+// Convert は状態図をPlantUML形式に変換し、変換時の警告とともに返します
+func (c *StateDiagramConverter) Convert(input string, options ConvertOptions) (string, ParseErrors, error) {
+	lc, err := beginConvert(c.settingsParser, input, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	direction := options.Direction
+	if direction == "" {
+		direction = c.topLevelDirection(lc.lines)
+	}
+	state := &stateConversion{hint: directionLayouts[direction].hint}
+
+	var errs ParseErrors
+	for _, line := range lc.lines {
+		if err := c.convertLine(state, line); err != nil {
+			errs = append(errs, asParseError(err, line.Pos, ErrUnexpectedToken))
+		}
+	}
+	for len(state.writer.blocks) > 0 {
+		block := state.writer.blocks[len(state.writer.blocks)-1]
+		errs = append(errs, c.closeBlock(state, block.pos))
+	}
+
+	var body strings.Builder
+	body.WriteString("hide empty description\n")
+	if layout := directionLayouts[direction]; layout.directive != "" {
+		body.WriteString(layout.directive + "\n")
+	}
+	body.WriteString(state.writer.body.String())
+	return lc.finish(body.String(), errs, state.warnings)
+}
+
+// topLevelDirection は複合状態の外で指定された図の向きを返します
+// 状態遷移の矢印に方向ヒントを埋め込むため、変換の前に読み取ります
+func (c *StateDiagramConverter) topLevelDirection(lines []sourceLine) string {
+	depth := 0
+	for _, line := range lines {
+		switch {
+		case strings.HasSuffix(line.Text, "{"):
+			depth++
+		case line.Text == "}":
+			depth--
+		case depth == 0 && directionStatementPattern.MatchString(line.Text):
+			direction := strings.ToUpper(directionStatementPattern.FindStringSubmatch(line.Text)[1])
+			if _, ok := directionLayouts[direction]; ok {
+				return direction
+			}
+		}
+	}
+	return ""
+}
+
+// convertLine は1行をPlantUMLに変換して書き出します
+// PlantUMLで表現できない行は読み飛ばして警告を記録します
+func (c *StateDiagramConverter) convertLine(state *stateConversion, line sourceLine) error {
+	w := &state.writer
+	text := line.Text
+
+	if w.top() == "note" {
+		if text == "end note" {
+			w.pop()
+		}
+		w.writeLine(text, false)
+		return nil
+	}
+
+	switch {
+	case text == "}":
+		if w.top() != "state" {
+			return newParseError(line.Pos, ErrUnexpectedToken, "対応する複合状態のない } です")
+		}
+		w.pop()
+		w.writeLine("}", false)
+
+	case text == "--":
+		if w.top() != "state" {
+			return newParseError(line.Pos, ErrUnexpectedToken, "-- は複合状態の中でのみ使用できます")
+		}
+		w.writeLine("--", true)
+
+	case directionStatementPattern.MatchString(text):
+		direction := strings.ToUpper(directionStatementPattern.FindStringSubmatch(text)[1])
+		if _, ok := directionLayouts[direction]; !ok {
+			return newParseError(line.Pos, ErrInvalidDirection, "サポートされていない図の向き: %s", direction)
+		}
+		if len(w.blocks) > 0 {
+			state.warnings = append(state.warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLでは複合状態ごとの向きを指定できないため無視します"))
+		}
+
+	case styleStatementPattern.MatchString(text):
+		keyword := styleStatementPattern.FindStringSubmatch(text)[1]
+		state.warnings = append(state.warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLの状態図では %s を表現できないため無視します", keyword))
+
+	case strings.HasPrefix(text, "note "):
+		position, name, body, err := c.stateParser.ParseNote(text)
+		if err != nil {
+			return asParseError(err, line.Pos, ErrUnexpectedToken)
+		}
+		if body != "" {
+			w.writeLine(fmt.Sprintf("note %s of %s : %s", position, c.stateParser.stateName(name), body), false)
+			return nil
+		}
+		w.writeLine(fmt.Sprintf("note %s of %s", position, c.stateParser.stateName(name)), false)
+		w.push("note", line.Pos)
+
+	case strings.HasPrefix(text, "state "):
+		definition, err := c.stateParser.ParseState(text)
+		if err != nil {
+			return asParseError(err, line.Pos, ErrInvalidState)
+		}
+		w.writeLine(c.stateParser.FormatState(definition), false)
+		if definition.Composite {
+			w.push("state", line.Pos)
+		}
+
+	case c.stateParser.IsTransition(text):
+		if cssClassPattern.MatchString(text) {
+			state.warnings = append(state.warnings, newParseError(line.Pos, ErrUnsupported, "PlantUMLの状態図ではスタイルクラスを表現できないため無視します"))
+			text = cssClassPattern.ReplaceAllString(text, "")
+		}
+		transition, err := c.stateParser.ParseTransition(text)
+		if err != nil {
+			return asParseError(err, line.Pos, ErrInvalidRelation)
+		}
+		transition.LayoutDirection = state.hint
+		w.writeLine(c.stateParser.FormatTransition(transition), false)
+
+	default:
+		name, description, err := c.stateParser.ParseDescription(text)
+		if err != nil {
+			return asParseError(err, line.Pos, ErrUnexpectedToken)
+		}
+		if description == "" {
+			w.writeLine("state "+c.stateParser.stateName(name), false)
+		} else {
+			w.writeLine(fmt.Sprintf("%s : %s", c.stateParser.stateName(name), description), false)
+		}
+	}
+	return nil
+}
+
+// closeBlock は閉じられていない複合状態・ノートを閉じ、そのエラーを返します
+func (c *StateDiagramConverter) closeBlock(state *stateConversion, pos Pos) *ParseError {
+	w := &state.writer
+	if w.pop().kind == "note" {
+		w.writeLine("end note", false)
+		return newParseError(pos, ErrUnexpectedToken, "ノートが end note で閉じられていません")
+	}
+	w.writeLine("}", false)
+	return newParseError(pos, ErrUnexpectedToken, "複合状態が } で閉じられていません")
+}
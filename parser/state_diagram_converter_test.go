@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestStateDiagramConverter_Convert(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		direction string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:  "開始・終了状態とラベル",
+			input: "stateDiagram-v2\n[*] --> Pending\nPending --> Paid : 入金\nPaid --> [*]",
+			want:  "@startuml\nhide empty description\n[*] --> Pending\nPending --> Paid : 入金\nPaid --> [*]\n@enduml",
+		},
+		{
+			name:  "状態の説明",
+			input: "stateDiagram\nstate \"支払い待ち\" as Pending\nPaid : 支払い済み\nIdle",
+			want:  "@startuml\nhide empty description\nstate \"支払い待ち\" as Pending\nPaid : 支払い済み\nstate Idle\n@enduml",
+		},
+		{
+			name:  "矢印を含む説明",
+			input: "stateDiagram-v2\nMoving : speed --> fast",
+			want:  "@startuml\nhide empty description\nMoving : speed --> fast\n@enduml",
+		},
+		{
+			name: "複合状態と並行状態",
+			input: `stateDiagram-v2
+state Shipping {
+    [*] --> Picking
+    state Picking {
+        [*] --> Scan
+    }
+    --
+    [*] --> Invoice
+}`,
+			want: `@startuml
+hide empty description
+state Shipping {
+    [*] --> Picking
+    state Picking {
+        [*] --> Scan
+    }
+--
+    [*] --> Invoice
+}
+@enduml`,
+		},
+		{
+			name:  "擬似状態",
+			input: "stateDiagram-v2\nstate check <<choice>>\nstate split <<fork>>\nstate merge <<join>>\ncheck --> split : 在庫あり",
+			want:  "@startuml\nhide empty description\nstate check <<choice>>\nstate split <<fork>>\nstate merge <<join>>\ncheck --> split : 在庫あり\n@enduml",
+		},
+		{
+			name:  "ノート",
+			input: "stateDiagram-v2\nnote right of Paid : 入金確認済み\nnote left of Pending\n    入金を\n    待つ\nend note",
+			want:  "@startuml\nhide empty description\nnote right of Paid : 入金確認済み\nnote left of Pending\n    入金を\n    待つ\nend note\n@enduml",
+		},
+		{
+			name:  "図の向き",
+			input: "---\ntitle: 注文\n---\nstateDiagram-v2\n[*] --> A\ndirection LR",
+			want:  "@startuml\ntitle 注文\nhide empty description\nleft to right direction\n[*] --> A\n@enduml",
+		},
+		{
+			name:      "向きの上書き",
+			input:     "stateDiagram-v2\ndirection LR\n[*] --> A",
+			direction: "BT",
+			want:      "@startuml\nhide empty description\n[*] -up-> A\n@enduml",
+		},
+		{
+			name:    "閉じられていない複合状態",
+			input:   "stateDiagram-v2\nstate Shipping {\n[*] --> Picking",
+			wantErr: true,
+		},
+		{
+			name:    "複合状態の外の並行状態",
+			input:   "stateDiagram-v2\n[*] --> A\n--\n[*] --> B",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := NewStateDiagramConverter().Convert(tt.input, ConvertOptions{Direction: tt.direction})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateDiagramConverter_Errors(t *testing.T) {
+	input := "stateDiagram-v2\nclassDef done fill:#f00\nA:::done --> B\nA --> \nstate X <<history>>\nnote left of B\nx"
+	errs := []ParseError{
+		{Line: 4, Column: 1, Snippet: "A --> ", Code: ErrInvalidRelation, Message: "状態遷移を解釈できません: A -->"},
+		{Line: 5, Column: 1, Snippet: "state X <<history>>", Code: ErrInvalidState, Message: "サポートされていない状態の種類です: <<history>>"},
+		{Line: 6, Column: 1, Snippet: "note left of B", Code: ErrUnexpectedToken, Message: "ノートが end note で閉じられていません"},
+	}
+	unsupported := []ParseError{
+		{Line: 2, Column: 1, Snippet: "classDef done fill:#f00", Code: ErrUnsupported, Message: "PlantUMLの状態図では classDef を表現できないため無視します"},
+		{Line: 3, Column: 1, Snippet: "A:::done --> B", Code: ErrUnsupported, Message: "PlantUMLの状態図ではスタイルクラスを表現できないため無視します"},
+	}
+
+	runConverterErrorTests(t, NewStateDiagramConverter(), []converterErrorTest{
+		{name: "通常モード", input: input, wantErrors: errs},
+		{
+			name:         "lenient モード",
+			input:        input,
+			mode:         ModeLenient,
+			want:         "@startuml\nhide empty description\nA --> B\nnote left of B\n    x\nend note\n@enduml",
+			wantWarnings: append(unsupported, errs...),
+		},
+		{name: "strict モード", input: "stateDiagram-v2\nclassDef done fill:#f00", mode: ModeStrict, wantErrors: unsupported[:1]},
+	})
+}
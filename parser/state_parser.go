@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StateParser は状態図の状態の宣言・状態遷移・ノートの解析を担当します
+type StateParser struct {
+	statePattern       *regexp.Regexp
+	transitionPattern  *regexp.Regexp
+	notePattern        *regexp.Regexp
+	descriptionPattern *regexp.Regexp
+}
+
+// NewStateParser は新しいStateParserインスタンスを作成します
+func NewStateParser() *StateParser {
+	return &StateParser{
+		statePattern:       regexp.MustCompile(`^state\s+(?:"([^"]*)"\s+as\s+)?([\p{L}\p{N}_.]+)\s*(?:<<(\w+)>>)?\s*(\{)?$`),
+		transitionPattern:  regexp.MustCompile(`^(\[\*\]|[\p{L}\p{N}_.]+)\s*-->\s*(\[\*\]|[\p{L}\p{N}_.]+)\s*(?::\s*(.*))?$`),
+		notePattern:        regexp.MustCompile(`^note\s+(left|right)\s+of\s+([\p{L}\p{N}_.]+)\s*(?::\s*(.*))?$`),
+		descriptionPattern: regexp.MustCompile(`^([\p{L}\p{N}_.]+)\s*:\s*([^:].*)?$`),
+	}
+}
+
+// stateKinds はPlantUMLで表現できる擬似状態の種類です
+var stateKinds = map[string]bool{
+	"choice": true,
+	"fork":   true,
+	"join":   true,
+}
+
+// ParseState は状態の宣言（state "説明" as X / state X <<choice>> / state X {）を解析します
+func (p *StateParser) ParseState(line string) (*StateDefinition, error) {
+	matches := p.statePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("状態の宣言を解釈できません: %s", line)
+	}
+	if matches[3] != "" && !stateKinds[matches[3]] {
+		return nil, fmt.Errorf("サポートされていない状態の種類です: <<%s>>", matches[3])
+	}
+	if matches[3] != "" && matches[4] != "" {
+		return nil, fmt.Errorf("%s は <<%s>> のため内部の状態を持てません", matches[2], matches[3])
+	}
+
+	return &StateDefinition{
+		Name:        matches[2],
+		Description: matches[1],
+		Kind:        matches[3],
+		Composite:   matches[4] != "",
+	}, nil
+}
+
+// IsTransition は行が状態遷移（A --> B）かどうかを判定します
+// 説明（X : 説明）の中に現れる --> は状態遷移として扱いません
+func (p *StateParser) IsTransition(line string) bool {
+	return !p.descriptionPattern.MatchString(line) && strings.Contains(line, "-->")
+}
+
+// ParseTransition は状態遷移（A --> B : ラベル）を解析します
+func (p *StateParser) ParseTransition(line string) (*StateTransition, error) {
+	matches := p.transitionPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("状態遷移を解釈できません: %s", line)
+	}
+	return &StateTransition{
+		Source: matches[1],
+		Target: matches[2],
+		Label:  strings.TrimSpace(matches[3]),
+	}, nil
+}
+
+// ParseNote はノートの開始行（note right of X : 本文）を解析し、位置・状態名・本文を返します
+// 本文がない場合は、end note までの複数行のノートの開始行です
+func (p *StateParser) ParseNote(line string) (string, string, string, error) {
+	matches := p.notePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return "", "", "", fmt.Errorf("ノートを解釈できません: %s", line)
+	}
+	return matches[1], matches[2], strings.TrimSpace(matches[3]), nil
+}
+
+// ParseDescription は状態の説明（X : 説明）を解析し、状態名と説明を返します
+// 状態名だけの行は説明のない状態の宣言として扱います
+func (p *StateParser) ParseDescription(line string) (string, string, error) {
+	if matches := p.descriptionPattern.FindStringSubmatch(line); matches != nil {
+		return matches[1], strings.TrimSpace(matches[2]), nil
+	}
+	if plantUMLIdentPattern.MatchString(line) {
+		return line, "", nil
+	}
+	return "", "", fmt.Errorf("解釈できない行です: %s", line)
+}
+
+// FormatState は状態の宣言をPlantUML形式の1行にフォーマットします
+// 複合状態の場合は行末に { を付けます
+func (p *StateParser) FormatState(state *StateDefinition) string {
+	line := "state "
+	if state.Description != "" {
+		line += fmt.Sprintf("\"%s\" as ", state.Description)
+	}
+	line += p.stateName(state.Name)
+	if state.Kind != "" {
+		line += fmt.Sprintf(" <<%s>>", state.Kind)
+	}
+	if state.Composite {
+		line += " {"
+	}
+	return line
+}
+
+// FormatTransition は状態遷移をPlantUML形式の1行にフォーマットします
+func (p *StateParser) FormatTransition(transition *StateTransition) string {
+	arrow := "-->"
+	if transition.LayoutDirection != "" {
+		arrow = "-" + transition.LayoutDirection + "->"
+	}
+
+	line := fmt.Sprintf("%s %s %s", p.stateName(transition.Source), arrow, p.stateName(transition.Target))
+	if transition.Label != "" {
+		line += " : " + transition.Label
+	}
+	return line
+}
+
+// stateName は状態名をPlantUMLで参照できる形にします
+// 開始状態・終了状態の [*] はそのまま使用します
+func (p *StateParser) stateName(name string) string {
+	if name == "[*]" {
+		return name
+	}
+	return PlantUMLName(name)
+}
@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStateParser_ParseState(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *StateDefinition
+		wantErr bool
+	}{
+		{
+			name: "説明付きの状態",
+			line: `state "支払い待ち" as Pending`,
+			want: &StateDefinition{Name: "Pending", Description: "支払い待ち"},
+		},
+		{
+			name: "複合状態",
+			line: "state Shipping {",
+			want: &StateDefinition{Name: "Shipping", Composite: true},
+		},
+		{
+			name: "分岐",
+			line: "state check <<choice>>",
+			want: &StateDefinition{Name: "check", Kind: "choice"},
+		},
+		{
+			name:    "サポートされていない種類",
+			line:    "state history <<history>>",
+			wantErr: true,
+		},
+		{
+			name:    "内部の状態を持つ擬似状態",
+			line:    "state split <<fork>> {",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewStateParser().ParseState(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseState() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateParser_ParseTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *StateTransition
+		wantErr bool
+	}{
+		{
+			name: "開始状態からの遷移",
+			line: "[*] --> Pending",
+			want: &StateTransition{Source: "[*]", Target: "Pending"},
+		},
+		{
+			name: "ラベル付きの遷移",
+			line: "Pending-->Paid : 入金",
+			want: &StateTransition{Source: "Pending", Target: "Paid", Label: "入金"},
+		},
+		{
+			name:    "遷移先のない遷移",
+			line:    "Pending -->",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewStateParser().ParseTransition(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTransition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTransition() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateParser_IsTransition(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want bool
+	}{
+		{name: "状態遷移", line: "Pending --> Paid : 入金", want: true},
+		{name: "遷移先のない遷移", line: "Pending -->", want: true},
+		{name: "矢印を含む説明", line: "Moving : speed --> fast", want: false},
+		{name: "状態の説明", line: "Moving : 移動中", want: false},
+		{name: "スタイルクラス付きの遷移", line: "A:::done --> B", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewStateParser().IsTransition(tt.line); got != tt.want {
+				t.Errorf("IsTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateParser_ParseNote(t *testing.T) {
+	position, name, body, err := NewStateParser().ParseNote("note right of Paid : 入金確認済み")
+	if err != nil || position != "right" || name != "Paid" || body != "入金確認済み" {
+		t.Errorf("ParseNote() got = %q %q %q, error = %v", position, name, body, err)
+	}
+
+	if _, _, _, err := NewStateParser().ParseNote("note over Paid : x"); err == nil {
+		t.Errorf("ParseNote() error = nil, want error")
+	}
+}
+
+func TestStateParser_ParseDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		line            string
+		wantName        string
+		wantDescription string
+		wantErr         bool
+	}{
+		{name: "説明", line: "Paid : 支払い済み", wantName: "Paid", wantDescription: "支払い済み"},
+		{name: "状態名のみ", line: "Idle", wantName: "Idle"},
+		{name: "解釈できない行", line: "Idle Paid", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, description, err := NewStateParser().ParseDescription(tt.line)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDescription() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || description != tt.wantDescription {
+				t.Errorf("ParseDescription() got = %q %q, want %q %q", name, description, tt.wantName, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestStateParser_Format(t *testing.T) {
+	p := NewStateParser()
+
+	states := map[string]*StateDefinition{
+		`state "支払い待ち" as Pending`:  {Name: "Pending", Description: "支払い待ち"},
+		"state Shipping {":          {Name: "Shipping", Composite: true},
+		"state join_state <<join>>": {Name: "join_state", Kind: "join"},
+	}
+	for want, state := range states {
+		if got := p.FormatState(state); got != want {
+			t.Errorf("FormatState() got = %v, want %v", got, want)
+		}
+	}
+
+	transitions := map[string]*StateTransition{
+		"[*] --> Pending":     {Source: "[*]", Target: "Pending"},
+		"Paid -up-> [*] : 完了": {Source: "Paid", Target: "[*]", Label: "完了", LayoutDirection: "up"},
+	}
+	for want, transition := range transitions {
+		if got := p.FormatTransition(transition); got != want {
+			t.Errorf("FormatTransition() got = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StyleParser はMermaidのスタイル指定（fill:#f9f,stroke:#333）の解析を担当します
+type StyleParser struct{}
+
+// NewStyleParser は新しいStyleParserインスタンスを作成します
+func NewStyleParser() *StyleParser {
+	return &StyleParser{}
+}
+
+// ParseStyle はCSS形式のスタイル指定を解析します
+// サポートしていない属性は無視し、警告メッセージとして返します
+func (p *StyleParser) ParseStyle(text string) (*ClassStyle, []string) {
+	style := &ClassStyle{}
+	var warnings []string
+
+	for _, declaration := range strings.Split(text, ",") {
+		declaration = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(declaration), ";"))
+		if declaration == "" {
+			continue
+		}
+
+		property, value, ok := strings.Cut(declaration, ":")
+		property = strings.TrimSpace(property)
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			warnings = append(warnings, fmt.Sprintf("スタイル指定を解釈できません: %q", declaration))
+			continue
+		}
+
+		switch property {
+		case "fill":
+			style.Fill = value
+		case "stroke":
+			style.Stroke = value
+		case "stroke-width":
+			style.StrokeWidth = value
+		case "color":
+			style.FontColor = value
+		default:
+			warnings = append(warnings, fmt.Sprintf("サポートされていないスタイル属性です: %s", property))
+		}
+	}
+
+	return style, warnings
+}
+
+// MergeStyle は src で指定された属性を dst に上書きします
+func (p *StyleParser) MergeStyle(dst *ClassStyle, src *ClassStyle) {
+	if src.Fill != "" {
+		dst.Fill = src.Fill
+	}
+	if src.Stroke != "" {
+		dst.Stroke = src.Stroke
+	}
+	if src.StrokeWidth != "" {
+		dst.StrokeWidth = src.StrokeWidth
+	}
+	if src.FontColor != "" {
+		dst.FontColor = src.FontColor
+	}
+}
+
+// FormatInlineColor はクラス宣言の後ろに付けるPlantUMLの色指定（#back:f9f;line:333）にフォーマットします
+func (p *StyleParser) FormatInlineColor(style *ClassStyle) string {
+	var specs []string
+	if style.Fill != "" {
+		specs = append(specs, "back:"+p.color(style.Fill))
+	}
+	if style.Stroke != "" {
+		specs = append(specs, "line:"+p.color(style.Stroke))
+	}
+	if p.isBold(style.StrokeWidth) {
+		specs = append(specs, "line.bold")
+	}
+	if style.FontColor != "" {
+		specs = append(specs, "text:"+p.color(style.FontColor))
+	}
+
+	if len(specs) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(specs, ";")
+}
+
+// FormatSkinparam はスタイルクラスを skinparam class<<name>> ブロックにフォーマットします
+// Mermaidの default クラスはすべてのクラスに適用されるため、ステレオタイプなしの skinparam class にします
+func (p *StyleParser) FormatSkinparam(name string, style *ClassStyle) string {
+	target := fmt.Sprintf("class<<%s>>", name)
+	if name == "default" {
+		target = "class"
+	}
+
+	var block strings.Builder
+	block.WriteString(fmt.Sprintf("skinparam %s {\n", target))
+	if style.Fill != "" {
+		block.WriteString(fmt.Sprintf("    BackgroundColor %s\n", style.Fill))
+	}
+	if style.Stroke != "" {
+		block.WriteString(fmt.Sprintf("    BorderColor %s\n", style.Stroke))
+	}
+	if thickness := p.thickness(style.StrokeWidth); thickness != "" {
+		block.WriteString(fmt.Sprintf("    BorderThickness %s\n", thickness))
+	}
+	if style.FontColor != "" {
+		block.WriteString(fmt.Sprintf("    FontColor %s\n", style.FontColor))
+	}
+	block.WriteString("}\n")
+	return block.String()
+}
+
+// color はインラインの色指定用に先頭の # を取り除きます
+func (p *StyleParser) color(value string) string {
+	return strings.TrimPrefix(value, "#")
+}
+
+// thickness は stroke-width の値から単位を取り除いた線の太さを返します
+func (p *StyleParser) thickness(width string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(width), "px"))
+}
+
+// isBold は線の太さがPlantUMLの太線（line.bold）に相当するかどうかを判定します
+func (p *StyleParser) isBold(width string) bool {
+	thickness := p.thickness(width)
+	return thickness != "" && thickness != "0" && thickness != "1"
+}
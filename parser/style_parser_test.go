@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStyleParser_ParseStyle(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         *ClassStyle
+		wantWarnings []string
+	}{
+		{
+			name:  "色と線",
+			input: "fill:#f9f,stroke:#333,stroke-width:4px,color:#fff",
+			want:  &ClassStyle{Fill: "#f9f", Stroke: "#333", StrokeWidth: "4px", FontColor: "#fff"},
+		},
+		{
+			name:  "空白と末尾のセミコロン",
+			input: "fill: red ; , stroke:blue;",
+			want:  &ClassStyle{Fill: "red", Stroke: "blue"},
+		},
+		{
+			name:         "サポートされていない属性",
+			input:        "fill:#f9f,stroke-dasharray:5 5",
+			want:         &ClassStyle{Fill: "#f9f"},
+			wantWarnings: []string{"サポートされていないスタイル属性です: stroke-dasharray"},
+		},
+		{
+			name:         "値のない属性",
+			input:        "fill",
+			want:         &ClassStyle{},
+			wantWarnings: []string{`スタイル指定を解釈できません: "fill"`},
+		},
+	}
+
+	p := NewStyleParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings := p.ParseStyle(tt.input)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStyle() got = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("ParseStyle() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestStyleParser_FormatInlineColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		style *ClassStyle
+		want  string
+	}{
+		{
+			name:  "すべての属性",
+			style: &ClassStyle{Fill: "#f9f", Stroke: "#333", StrokeWidth: "4px", FontColor: "#fff"},
+			want:  "#back:f9f;line:333;line.bold;text:fff",
+		},
+		{
+			name:  "細い線",
+			style: &ClassStyle{Stroke: "red", StrokeWidth: "1px"},
+			want:  "#line:red",
+		},
+		{
+			name:  "指定なし",
+			style: &ClassStyle{},
+			want:  "",
+		},
+	}
+
+	p := NewStyleParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.FormatInlineColor(tt.style); got != tt.want {
+				t.Errorf("FormatInlineColor() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyleParser_FormatSkinparam(t *testing.T) {
+	tests := []struct {
+		name      string
+		className string
+		style     *ClassStyle
+		want      string
+	}{
+		{
+			name:      "スタイルクラス",
+			className: "hot",
+			style:     &ClassStyle{Fill: "#f96", StrokeWidth: "2px"},
+			want:      "skinparam class<<hot>> {\n    BackgroundColor #f96\n    BorderThickness 2\n}\n",
+		},
+		{
+			name:      "既定のスタイルクラス",
+			className: "default",
+			style:     &ClassStyle{Stroke: "#333", FontColor: "black"},
+			want:      "skinparam class {\n    BorderColor #333\n    FontColor black\n}\n",
+		},
+	}
+
+	p := NewStyleParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.FormatSkinparam(tt.className, tt.style); got != tt.want {
+				t.Errorf("FormatSkinparam() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
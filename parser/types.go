@@ -1,9 +1,43 @@
 package parser
 
 // ClassDefinition はクラス定義の内容を表現します
+// Label は表示名、Namespace は所属する名前空間で、入れ子の場合は "Billing.Invoices" のようにドットで連結します
+// Link と Tooltip は click / link で指定されたリンク先とツールチップです
 type ClassDefinition struct {
-	Members []string
-	IsEnum  bool
+	Name           string
+	Label          string
+	TypeParameters string
+	Namespace      string
+	Annotations    []string
+	CSSClasses     []string
+	Style          *ClassStyle
+	Link           string
+	Tooltip        string
+	Members        []string
+	IsEnum         bool
+}
+
+// ClassStyle はクラスの色と枠線のスタイルを表現します
+type ClassStyle struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth string
+	FontColor   string
+}
+
+// DiagramConfig は図全体の見た目の設定（テーマ・描画スタイルなど）を表現します
+// Theme と Look はMermaidでの名前、ThemeVariables はMermaidのテーマ変数名をキーとします
+type DiagramConfig struct {
+	Theme            string
+	Look             string
+	ThemeVariables   map[string]string
+	HideEmptyMembers bool
+}
+
+// StyleClass は classDef で定義されたスタイルクラスを表現します
+type StyleClass struct {
+	Name  string
+	Style *ClassStyle
 }
 
 // Relationship はクラス間の関連を表現します
@@ -13,6 +47,108 @@ type Relationship struct {
 	Type       string
 	SourceMult string
 	TargetMult string
+	Label      string
+	// ラベルを読む方向（"<" または ">"）
+	LabelDirection string
+	// 配置の方向ヒント（directionLayouts の hint）
+	LayoutDirection string
+}
+
+// SequenceStatement はシーケンス図の1行の文を表現します
+// Kind は文の種類（participant・destroy・activate・deactivate・note・block・branch・title・autonumber・end・unsupported・message）で、
+// Keyword は participant / actor・ノートの位置・ブロックや分岐のキーワードなど、種類ごとのキーワードです
+// Label は参加者の表示名・メッセージ・ノートの本文・ブロックのラベルなど、種類ごとの文字列です
+type SequenceStatement struct {
+	Kind         string
+	Keyword      string
+	Participants []string
+	Label        string
+	// メッセージのMermaidの矢印と、送信先の活性化（"+"）・非活性化（"-"）の指定
+	Arrow      string
+	Activation string
+	// create で途中から現れる参加者であることを表します
+	Created bool
+}
+
+// StateDefinition は状態図の状態の宣言を表現します
+// Kind は擬似状態の種類（choice・fork・join）で、通常の状態では空文字列です
+// Composite は { } で内部の状態を持つ複合状態であることを表します
+type StateDefinition struct {
+	Name        string
+	Description string
+	Kind        string
+	Composite   bool
+}
+
+// StateTransition は状態間の遷移を表現します
+// 開始状態・終了状態は [*] で表現します
+type StateTransition struct {
+	Source string
+	Target string
+	Label  string
+	// 配置の方向ヒント（directionLayouts の hint）
+	LayoutDirection string
+}
+
+// EntityDefinition はER図のエンティティを表現します
+// Label は表示名で、エンティティ名と同じ場合は空文字列です
+type EntityDefinition struct {
+	Name       string
+	Label      string
+	Attributes []*EntityAttribute
+}
+
+// EntityAttribute はエンティティの属性を表現します
+// Keys は PK / FK / UK のキーの指定です
+type EntityAttribute struct {
+	Type    string
+	Name    string
+	Keys    []string
+	Comment string
+}
+
+// EntityRelationship はエンティティ間の関連を表現します
+// カーディナリティは zero-or-one / exactly-one / zero-or-more / one-or-more のいずれかです
+// Identifying は識別関連（実線）であることを表し、非識別関連は点線で表現します
+type EntityRelationship struct {
+	Source            string
+	Target            string
+	SourceCardinality string
+	TargetCardinality string
+	Identifying       bool
+	Label             string
+	// 配置の方向ヒント（directionLayouts の hint）
+	LayoutDirection string
+}
+
+// FlowNode はフローチャートのノードを表現します
+// Shape はノードの形（rect・round・diamond など）、Subgraph は所属するサブグラフのIDです
+type FlowNode struct {
+	ID       string
+	Label    string
+	Shape    string
+	Subgraph string
+}
+
+// FlowEdge はフローチャートのノード間の矢印を表現します
+// Line は線の種類（solid・dotted・thick・invisible）、Head と Tail は終点側と始点側の矢じりの種類（arrow・none・circle・cross）です
+type FlowEdge struct {
+	From  string
+	To    string
+	Label string
+	Line  string
+	Head  string
+	Tail  string
+	// 配置の方向ヒント（directionLayouts の hint）
+	LayoutDirection string
+}
+
+// FlowSubgraph はフローチャートのサブグラフを表現します
+// Parent は入れ子の場合の親サブグラフのIDで、最上位では空文字列です
+type FlowSubgraph struct {
+	ID     string
+	Title  string
+	Parent string
 }
 
 // ClassMember はクラスのメンバー（属性やメソッド）を表現します
@@ -21,5 +157,15 @@ type ClassMember struct {
 	Name       string
 	Type       string
 	Parameters string
+	ReturnType string
 	IsMethod   bool
+	IsStatic   bool
+	IsAbstract bool
+}
+
+// Note は注釈を表現します
+// Class が空の場合はどのクラスにも属さない浮いた注釈です
+type Note struct {
+	Class string
+	Lines []string
 }